@@ -1,21 +1,395 @@
 package runscope
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/ewilde/go-runscope"
+	"github.com/go-ini/ini"
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	// defaultConfigureTimeout bounds the access token check made while configuring the provider
+	defaultConfigureTimeout = 2 * time.Minute
+
+	// dataSourceReadTimeout bounds a data source read, data sources do not support a timeouts block
+	dataSourceReadTimeout = 5 * time.Minute
+
+	// planTimeout bounds the lookups made to check a resource while planning, a diff has no timeouts block
+	planTimeout = 5 * time.Minute
+
+	// defaultCredentialsFile holds named profiles of runscope credentials
+	defaultCredentialsFile = "~/.runscope/credentials"
+	defaultProfile         = "default"
+
+	// defaultOAuthTokenURL is where oauth application credentials are exchanged for an access token
+	defaultOAuthTokenURL = "https://www.runscope.com/signin/oauth/access_token"
 )
 
 // Config contains runscope provider settings
 type config struct {
-	AccessToken string
-	APIURL      string
+	// AccessToken takes precedence over ClientID and ClientSecret, when neither
+	// is set the credentials are read from Profile in CredentialsFile. Credentials
+	// in the environment are only used when none of these are configured, see
+	// credentialsFromEnvironment
+	AccessToken     string
+	ClientID        string
+	ClientSecret    string
+	OAuthTokenURL   string
+	CredentialsFile string
+	Profile         string
+
+	APIURL         string
+	MaxRetries     int
+	RetryMaxWait   time.Duration
+	RequestTimeout time.Duration
+	TeamID         string
+	// ProxyURL overrides the proxy taken from the HTTP_PROXY and HTTPS_PROXY environment variables
+	ProxyURL string
+	// CABundle is a PEM encoded bundle, or the path to one, trusted along with the system roots
+	CABundle           string
+	InsecureSkipVerify bool
+	// StopContext is cancelled when terraform is interrupted, in flight requests are abandoned
+	StopContext context.Context
+}
+
+// runscopeClient is passed to resources and data sources, it is the runscope api
+// client along with the provider level defaults
+type runscopeClient struct {
+	*runscope.Client
+	TeamID  string
+	stopCtx context.Context
+}
+
+// newContext returns a context for a single resource operation, it is cancelled when
+// the timeout elapses or terraform is interrupted
+func (c *runscopeClient) newContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx := c.stopCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// teamID returns the team_uuid set on the resource, falling back to the provider team_uuid
+func (c *runscopeClient) teamID(d *schema.ResourceData) (string, error) {
+	if attr, ok := d.GetOk("team_uuid"); ok {
+		return attr.(string), nil
+	}
+
+	if c.TeamID != "" {
+		return c.TeamID, nil
+	}
+
+	return "", fmt.Errorf("team_uuid must be set, either on the resource or the provider")
 }
 
-func (c *config) client() (*runscope.Client, error) {
-	client := runscope.NewClient(c.APIURL, c.AccessToken)
+func (c *config) client() (*runscopeClient, error) {
+	transport, err := c.transport()
+	if err != nil {
+		return nil, err
+	}
+
+	stopCtx := c.StopContext
+	if stopCtx == nil {
+		stopCtx = context.Background()
+	}
+
+	ctx, cancel := context.WithTimeout(stopCtx, defaultConfigureTimeout)
+	defer cancel()
+
+	accessToken, err := c.accessToken(ctx, transport)
+	if err != nil {
+		return nil, err
+	}
+
+	client := runscope.NewClient(c.APIURL, accessToken)
+	client.HTTP.Transport = transport
+	client.UserAgent = userAgent()
+	client.MaxRetries = c.MaxRetries
+	if c.RetryMaxWait > 0 {
+		client.RetryMaxWait = c.RetryMaxWait
+	}
+	if c.RequestTimeout > 0 {
+		client.HTTP.Timeout = c.RequestTimeout
+	}
 
 	log.Printf("[INFO] runscope client configured for server %s", c.APIURL)
 
-	return client, nil
+	// validate the access token up front so misconfiguration fails before any resource is touched
+	account, err := client.ReadAccount(ctx)
+	if err != nil {
+		if runscope.IsUnauthorized(err) {
+			return nil, fmt.Errorf("Invalid runscope access_token, it was rejected by %s: %s", c.APIURL, err)
+		}
+
+		return nil, fmt.Errorf("Error validating runscope access_token against %s: %s", c.APIURL, err)
+	}
+
+	log.Printf("[INFO] runscope access token belongs to account: %s", account.Email)
+
+	return &runscopeClient{Client: client, TeamID: c.TeamID, stopCtx: stopCtx}, nil
+}
+
+// credentialsRejectedError is returned when the oauth token endpoint refuses the
+// client_id and client_secret of a runscope oauth application
+type credentialsRejectedError struct {
+	ClientID string
+	TokenURL string
+	Status   string
+	Reason   string
+}
+
+func (e *credentialsRejectedError) Error() string {
+	return strings.TrimSpace(fmt.Sprintf("Runscope oauth client credentials for client_id %s were rejected by %s: %s %s",
+		e.ClientID, e.TokenURL, e.Status, e.Reason))
+}
+
+// isCredentialsRejected returns true when err is a credentialsRejectedError
+func isCredentialsRejected(err error) bool {
+	_, ok := err.(*credentialsRejectedError)
+	return ok
+}
+
+// credentialsFromEnvironment fills in the credentials not set in the provider block from
+// RUNSCOPE_ACCESS_TOKEN, RUNSCOPE_CLIENT_ID, RUNSCOPE_CLIENT_SECRET and RUNSCOPE_PROFILE.
+// An explicitly configured profile wins over credentials in the environment.
+func (c *config) credentialsFromEnvironment() {
+	if c.Profile != "" && c.AccessToken == "" && c.ClientID == "" && c.ClientSecret == "" {
+		for _, name := range []string{"RUNSCOPE_ACCESS_TOKEN", "RUNSCOPE_CLIENT_ID", "RUNSCOPE_CLIENT_SECRET"} {
+			if os.Getenv(name) != "" {
+				log.Printf("[WARN] %s is ignored, profile %s is set in the provider configuration", name, c.Profile)
+			}
+		}
+
+		return
+	}
+
+	if c.AccessToken == "" {
+		c.AccessToken = os.Getenv("RUNSCOPE_ACCESS_TOKEN")
+	}
+
+	if c.ClientID == "" {
+		c.ClientID = os.Getenv("RUNSCOPE_CLIENT_ID")
+	}
+
+	if c.ClientSecret == "" {
+		c.ClientSecret = os.Getenv("RUNSCOPE_CLIENT_SECRET")
+	}
+
+	if c.Profile == "" {
+		c.Profile = os.Getenv("RUNSCOPE_PROFILE")
+	}
+}
+
+// accessToken returns the token used to authenticate with the runscope api. A configured
+// access_token is used as is, oauth application credentials are exchanged for a token,
+// otherwise the credentials of the profile are used.
+func (c *config) accessToken(ctx context.Context, transport http.RoundTripper) (string, error) {
+	if c.AccessToken == "" && c.ClientID == "" && c.ClientSecret == "" {
+		if err := c.loadProfile(); err != nil {
+			return "", err
+		}
+	}
+
+	if c.AccessToken != "" {
+		return c.AccessToken, nil
+	}
+
+	if c.ClientID == "" || c.ClientSecret == "" {
+		if c.ClientID != "" || c.ClientSecret != "" {
+			return "", fmt.Errorf("Both client_id and client_secret must be set to authenticate with a runscope oauth application")
+		}
+
+		return "", fmt.Errorf("No runscope credentials found, set access_token, client_id and client_secret, or a profile in %s",
+			c.credentialsFile())
+	}
+
+	return c.exchangeClientCredentials(ctx, transport)
+}
+
+// loadProfile fills in the credentials and team_uuid that are not set from the profile
+// in the credentials file. A missing file is only an error when a profile was chosen.
+func (c *config) loadProfile() error {
+	profile := c.Profile
+	if profile == "" {
+		profile = defaultProfile
+	}
+
+	path, err := homedir.Expand(c.credentialsFile())
+	if err != nil {
+		return fmt.Errorf("Error expanding runscope credentials file path %s: %s", c.credentialsFile(), err)
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) && profile == defaultProfile {
+		log.Printf("[DEBUG] runscope credentials file %s does not exist", path)
+		return nil
+	}
+
+	file, err := ini.Load(path)
+	if err != nil {
+		return fmt.Errorf("Error reading runscope credentials file %s: %s", path, err)
+	}
+
+	section, err := file.GetSection(profile)
+	if err != nil {
+		return fmt.Errorf("Profile %s not found in runscope credentials file %s", profile, path)
+	}
+
+	log.Printf("[INFO] Using runscope credentials from profile %s in %s", profile, path)
+	c.AccessToken = section.Key("access_token").String()
+	c.ClientID = section.Key("client_id").String()
+	c.ClientSecret = section.Key("client_secret").String()
+	if c.TeamID == "" {
+		c.TeamID = section.Key("team_uuid").String()
+	}
+
+	return nil
+}
+
+func (c *config) credentialsFile() string {
+	if c.CredentialsFile == "" {
+		return defaultCredentialsFile
+	}
+
+	return c.CredentialsFile
+}
+
+// exchangeClientCredentials exchanges the client id and secret of a runscope oauth
+// application for an access token using the client credentials grant
+func (c *config) exchangeClientCredentials(ctx context.Context, transport http.RoundTripper) (string, error) {
+	tokenURL := c.OAuthTokenURL
+	if tokenURL == "" {
+		tokenURL = defaultOAuthTokenURL
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", c.ClientID)
+	form.Set("client_secret", c.ClientSecret)
+
+	req, err := http.NewRequest("POST", tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("Error creating runscope oauth token request: %s", err)
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", userAgent())
+
+	log.Printf("[DEBUG] requesting runscope access token for oauth client %s from %s", c.ClientID, tokenURL)
+	httpClient := &http.Client{Transport: transport, Timeout: c.RequestTimeout}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Error requesting runscope access token from %s: %s", tokenURL, err)
+	}
+	defer resp.Body.Close()
+
+	// the body is not included in errors or logs, it holds the access token
+	token := struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}{}
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	json.Unmarshal(body, &token)
+	reason := strings.TrimSpace(strings.Join([]string{token.Error, token.ErrorDescription}, " "))
+	switch {
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized ||
+		resp.StatusCode == http.StatusForbidden:
+		return "", &credentialsRejectedError{ClientID: c.ClientID, TokenURL: tokenURL, Status: resp.Status, Reason: reason}
+	case resp.StatusCode >= 300:
+		return "", fmt.Errorf("Error requesting runscope access token for client_id %s from %s: %s",
+			c.ClientID, tokenURL, strings.TrimSpace(resp.Status+" "+reason))
+	case token.AccessToken == "":
+		return "", fmt.Errorf("Error requesting runscope access token for client_id %s from %s: no access_token in the response",
+			c.ClientID, tokenURL)
+	}
+
+	log.Printf("[INFO] runscope access token obtained for oauth client %s", c.ClientID)
+
+	return token.AccessToken, nil
+}
+
+// transport returns the http transport used to reach the runscope api, configured
+// with the provider proxy and tls settings
+func (c *config) transport() (*http.Transport, error) {
+	transport := cleanhttp.DefaultTransport()
+
+	if c.ProxyURL != "" {
+		proxyURL, err := url.Parse(c.ProxyURL)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("Invalid proxy_url %q, expected a url such as http://proxy.example.com:3128", c.ProxyURL)
+		}
+
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if c.CABundle != "" || c.InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+	}
+
+	if c.CABundle != "" {
+		bundle, err := readCABundle(c.CABundle)
+		if err != nil {
+			return nil, err
+		}
+
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			log.Printf("[WARN] Unable to load the system certificate pool, only trusting ca_bundle: %s", err)
+			roots = x509.NewCertPool()
+		}
+
+		if !roots.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("Invalid ca_bundle, no PEM encoded certificates were found")
+		}
+
+		transport.TLSClientConfig.RootCAs = roots
+	}
+
+	if c.InsecureSkipVerify {
+		log.Printf("[WARN] runscope api tls certificates are not verified, insecure_skip_verify is set")
+	}
+
+	return transport, nil
+}
+
+// readCABundle returns the PEM encoded ca_bundle, reading it from a file unless
+// the certificates were given inline
+func readCABundle(bundle string) ([]byte, error) {
+	if strings.Contains(bundle, "-----BEGIN") {
+		return []byte(bundle), nil
+	}
+
+	contents, err := ioutil.ReadFile(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading ca_bundle: %s", err)
+	}
+
+	return contents, nil
+}
+
+// userAgent identifies the provider in runscope api logs. The vendored terraform does not tell
+// providers the version of the terraform cli running them, so the terraform version given is
+// that of the terraform core library vendored into the provider.
+func userAgent() string {
+	return fmt.Sprintf("terraform-provider-runscope/%s (vendored terraform core %s)", providerVersion, terraform.VersionString())
 }
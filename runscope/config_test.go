@@ -0,0 +1,638 @@
+package runscope
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ewilde/go-runscope"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestConfigClient_invalidAccessToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"meta": {"status": "error"}, "data": [], "error": {"status": 401, "error": "Invalid access token"}}`))
+	}))
+	defer server.Close()
+
+	config := config{
+		AccessToken: "invalid",
+		APIURL:      server.URL,
+	}
+
+	_, err := config.client()
+	if err == nil {
+		t.Fatal("Expected an error configuring a client with an invalid access token")
+	}
+
+	if !strings.Contains(err.Error(), "Invalid runscope access_token") {
+		t.Fatalf("Expected an invalid access token error, actual: %s", err)
+	}
+}
+
+func TestConfigClient_retries(t *testing.T) {
+	cases := []struct {
+		method     string
+		status     int
+		maxRetries int
+		calls      int
+		expectErr  bool
+	}{
+		{"GET", http.StatusTooManyRequests, 3, 3, false},
+		{"GET", http.StatusServiceUnavailable, 3, 3, false},
+		{"GET", http.StatusServiceUnavailable, 1, 2, true},
+		{"POST", http.StatusTooManyRequests, 3, 3, false},
+		{"POST", http.StatusInternalServerError, 3, 1, true},
+	}
+
+	for _, c := range cases {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/account" {
+				w.Write([]byte(`{"meta": {"status": "success"}, "data": {"id": "account", "email": "owner@example.com"}}`))
+				return
+			}
+
+			calls++
+			if calls < 3 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(c.status)
+				w.Write([]byte(`{"meta": {"status": "error"}, "data": [], "error": {"status": 0, "error": "retry"}}`))
+				return
+			}
+
+			w.Write([]byte(`{"meta": {"status": "success"}, "data": {"key": "key", "name": "bucket", "team": {"id": "team"}}}`))
+		}))
+
+		config := config{
+			AccessToken:  "token",
+			APIURL:       server.URL,
+			MaxRetries:   c.maxRetries,
+			RetryMaxWait: time.Millisecond,
+		}
+
+		client, err := config.client()
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if c.method == "GET" {
+			_, err = client.ReadBucket(context.Background(), "key")
+		} else {
+			_, err = client.CreateSchedule(context.Background(), &runscope.Schedule{Interval: "1h"}, "key", "test")
+		}
+		server.Close()
+
+		if c.expectErr && err == nil {
+			t.Fatalf("%s %d: expected an error", c.method, c.status)
+		}
+
+		if !c.expectErr && err != nil {
+			t.Fatalf("%s %d: unexpected error %s", c.method, c.status, err)
+		}
+
+		if calls != c.calls {
+			t.Fatalf("%s %d: expected %d calls, actual %d", c.method, c.status, c.calls, calls)
+		}
+	}
+}
+
+func TestRunscopeClient_teamID(t *testing.T) {
+	cases := []struct {
+		resourceTeamID string
+		providerTeamID string
+		expected       string
+		expectErr      bool
+	}{
+		{"resource", "provider", "resource", false},
+		{"", "provider", "provider", false},
+		{"resource", "", "resource", false},
+		{"", "", "", true},
+	}
+
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourceRunscopeBucket().Schema, map[string]interface{}{
+			"name":      "bucket",
+			"team_uuid": c.resourceTeamID,
+		})
+
+		client := &runscopeClient{TeamID: c.providerTeamID}
+		teamID, err := client.teamID(d)
+		if c.expectErr != (err != nil) {
+			t.Fatalf("Resource %q provider %q: expected error %t, actual %v", c.resourceTeamID, c.providerTeamID, c.expectErr, err)
+		}
+
+		if teamID != c.expected {
+			t.Fatalf("Resource %q provider %q: expected %q, actual %q", c.resourceTeamID, c.providerTeamID, c.expected, teamID)
+		}
+	}
+}
+
+func TestConfigClient_stopContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"meta": {"status": "error"}, "data": [], "error": {"status": 503, "error": "unavailable"}}`))
+	}))
+	defer server.Close()
+
+	stopCtx, stop := context.WithCancel(context.Background())
+	config := config{
+		AccessToken:  "token",
+		APIURL:       server.URL,
+		MaxRetries:   3,
+		RetryMaxWait: time.Minute,
+		StopContext:  stopCtx,
+	}
+
+	// interrupt while the client waits to retry the access token check
+	time.AfterFunc(50*time.Millisecond, stop)
+
+	start := time.Now()
+	_, err := config.client()
+	if err == nil {
+		t.Fatal("Expected an error configuring a client that was interrupted")
+	}
+
+	if !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Fatalf("Expected a cancelled error, actual: %s", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("Expected the retry wait to be interrupted, took %s", elapsed)
+	}
+}
+
+func TestConfigClient_requestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		w.Write([]byte(`{"meta": {"status": "success"}, "data": {"id": "account", "email": "owner@example.com"}}`))
+	}))
+	defer server.Close()
+
+	config := config{
+		AccessToken:    "token",
+		APIURL:         server.URL,
+		RequestTimeout: 50 * time.Millisecond,
+	}
+
+	if _, err := config.client(); err == nil {
+		t.Fatal("Expected an error when the request takes longer than the request timeout")
+	}
+}
+
+func TestRunscopeClient_newContext(t *testing.T) {
+	stopCtx, stop := context.WithCancel(context.Background())
+	client := &runscopeClient{stopCtx: stopCtx}
+
+	ctx, cancel := client.newContext(time.Minute)
+	defer cancel()
+
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Minute {
+		t.Fatalf("Expected a deadline within a minute, actual %s", deadline)
+	}
+
+	stop()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the operation context to be cancelled with the stop context")
+	}
+}
+
+func TestConfigClient_userAgent(t *testing.T) {
+	var actual string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actual = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"meta": {"status": "success"}, "data": {"id": "account", "email": "owner@example.com"}}`))
+	}))
+	defer server.Close()
+
+	config := config{
+		AccessToken: "token",
+		APIURL:      server.URL,
+	}
+
+	if _, err := config.client(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := fmt.Sprintf("terraform-provider-runscope/%s (vendored terraform core %s)", providerVersion, terraform.VersionString())
+	if actual != expected {
+		t.Fatalf("Expected User-Agent %q, actual %q", expected, actual)
+	}
+}
+
+func TestConfigClient_proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.Write([]byte(`{"meta": {"status": "success"}, "data": {"id": "account", "email": "owner@example.com"}}`))
+	}))
+	defer proxy.Close()
+
+	config := config{
+		AccessToken: "token",
+		APIURL:      "http://runscope.example.com",
+		ProxyURL:    proxy.URL,
+	}
+
+	if _, err := config.client(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if proxied != "http://runscope.example.com/account" {
+		t.Fatalf("Expected the account request to be sent through the proxy, actual %q", proxied)
+	}
+
+	config.ProxyURL = "proxy.example.com"
+	if _, err := config.client(); err == nil || !strings.Contains(err.Error(), "Invalid proxy_url") {
+		t.Fatalf("Expected an invalid proxy_url error, actual %v", err)
+	}
+}
+
+func TestConfigClient_tls(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"meta": {"status": "success"}, "data": {"id": "account", "email": "owner@example.com"}}`))
+	}))
+	defer server.Close()
+
+	bundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	bundleFile, err := ioutil.TempFile("", "runscope-ca-bundle")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(bundleFile.Name())
+
+	bundleFile.WriteString(bundle)
+	bundleFile.Close()
+
+	cases := []struct {
+		name               string
+		caBundle           string
+		insecureSkipVerify bool
+		expectErr          string
+	}{
+		{"untrusted", "", false, "certificate"},
+		{"inline bundle", bundle, false, ""},
+		{"bundle file", bundleFile.Name(), false, ""},
+		{"missing bundle file", bundleFile.Name() + ".missing", false, "Error reading ca_bundle"},
+		{"invalid bundle", "-----BEGIN CERTIFICATE-----\ninvalid\n-----END CERTIFICATE-----", false, "Invalid ca_bundle"},
+		{"insecure", "", true, ""},
+	}
+
+	for _, c := range cases {
+		config := config{
+			AccessToken:        "token",
+			APIURL:             server.URL,
+			CABundle:           c.caBundle,
+			InsecureSkipVerify: c.insecureSkipVerify,
+		}
+
+		_, err := config.client()
+		if c.expectErr == "" && err != nil {
+			t.Fatalf("%s: unexpected error %s", c.name, err)
+		}
+
+		if c.expectErr != "" && (err == nil || !strings.Contains(err.Error(), c.expectErr)) {
+			t.Fatalf("%s: expected an error containing %q, actual %v", c.name, c.expectErr, err)
+		}
+	}
+}
+
+func TestConfigClient_redactsDebugLogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/account" {
+			w.Write([]byte(`{"meta": {"status": "success"}, "data": {"id": "account", "email": "owner@example.com"}}`))
+			return
+		}
+
+		if r.URL.Path == "/buckets" {
+			w.Write([]byte(`{"meta": {"status": "success"}, "data": {"key": "bucket", "name": "bucket", "auth_token": "secret-auth-token"}}`))
+			return
+		}
+
+		// steps are returned as the list of steps in the test
+		body, _ := ioutil.ReadAll(r.Body)
+		if strings.HasSuffix(r.URL.Path, "/steps") {
+			fmt.Fprintf(w, `{"meta": {"status": "success"}, "data": [%s]}`, body)
+			return
+		}
+
+		fmt.Fprintf(w, `{"meta": {"status": "success"}, "data": %s}`, body)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	output := log.Writer()
+	log.SetOutput(&logs)
+	defer log.SetOutput(output)
+
+	config := config{
+		AccessToken: "secret-access-token",
+		APIURL:      server.URL,
+	}
+
+	client, err := config.client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := client.CreateBucket(context.Background(), &runscope.Bucket{Name: "bucket", Team: &runscope.Team{ID: "team"}}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	environment := &runscope.Environment{
+		Name:              "environment",
+		InitialVariables:  map[string]string{"api_key": "secret-api-key"},
+		ClientCertificate: "secret-certificate",
+	}
+
+	if _, err := client.CreateSharedEnvironment(context.Background(), environment, &runscope.Bucket{Key: "bucket"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	step := &runscope.TestStep{
+		StepType: "request",
+		Method:   "GET",
+		URL:      "http://example.com",
+		Auth:     map[string]string{"auth_type": "basic", "username": "user", "password": "secret-password"},
+		Headers:  map[string][]string{"Authorization": {"Bearer secret-bearer"}, "Accept": {"application/json"}},
+	}
+
+	if _, err := client.CreateTestStep(context.Background(), step, "bucket", "test"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	log.Printf("[DEBUG] step create: %s", runscope.Redact(step))
+
+	for _, secret := range []string{"secret-access-token", "secret-auth-token", "secret-api-key", "secret-certificate", "secret-password", "secret-bearer"} {
+		if strings.Contains(logs.String(), secret) {
+			t.Fatalf("Expected %s to be redacted from the debug logs:\n%s", secret, logs.String())
+		}
+	}
+
+	for _, expected := range []string{"Authorization:[********]", `"api_key":"********"`, `"username":"user"`, `"Accept":["application/json"]`} {
+		if !strings.Contains(logs.String(), expected) {
+			t.Fatalf("Expected the debug logs to contain %s:\n%s", expected, logs.String())
+		}
+	}
+}
+
+// testAccountServer serves the account of the given access token and an oauth token endpoint
+// that issues oauth-token for the client-id and client-secret application credentials
+func testAccountServer(accessToken string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			r.ParseForm()
+			if r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("client_id") != "client-id" ||
+				r.PostForm.Get("client_secret") != "client-secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error": "invalid_client", "error_description": "unknown client"}`))
+				return
+			}
+
+			w.Write([]byte(`{"access_token": "oauth-token", "token_type": "bearer"}`))
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer "+accessToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"meta": {"status": "error"}, "data": [], "error": {"status": 401, "error": "Invalid access token"}}`))
+			return
+		}
+
+		w.Write([]byte(`{"meta": {"status": "success"}, "data": {"id": "account", "email": "owner@example.com"}}`))
+	}))
+}
+
+func TestConfigClient_profile(t *testing.T) {
+	server := testAccountServer("profile-token")
+	defer server.Close()
+
+	credentials, err := ioutil.TempFile("", "runscope-credentials")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(credentials.Name())
+
+	credentials.WriteString(`[default]
+access_token = default-token
+team_uuid    = default-team
+
+[other-team]
+access_token = profile-token
+team_uuid    = other-team
+`)
+	credentials.Close()
+
+	cases := []struct {
+		name            string
+		credentialsFile string
+		profile         string
+		teamID          string
+		expectedTeamID  string
+		expectErr       string
+	}{
+		{"profile", credentials.Name(), "other-team", "", "other-team", ""},
+		{"provider team", credentials.Name(), "other-team", "provider-team", "provider-team", ""},
+		{"default profile", credentials.Name(), "", "", "", "Invalid runscope access_token"},
+		{"missing profile", credentials.Name(), "missing", "", "", "Profile missing not found"},
+		{"missing file", credentials.Name() + ".missing", "other-team", "", "", "Error reading runscope credentials file"},
+		{"missing default file", credentials.Name() + ".missing", "", "", "", "No runscope credentials found"},
+	}
+
+	for _, c := range cases {
+		config := config{
+			APIURL:          server.URL,
+			CredentialsFile: c.credentialsFile,
+			Profile:         c.profile,
+			TeamID:          c.teamID,
+		}
+
+		client, err := config.client()
+		if c.expectErr != "" {
+			if err == nil || !strings.Contains(err.Error(), c.expectErr) {
+				t.Fatalf("%s: expected an error containing %q, actual %v", c.name, c.expectErr, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s: unexpected error %s", c.name, err)
+		}
+
+		if client.TeamID != c.expectedTeamID {
+			t.Fatalf("%s: expected team %q, actual %q", c.name, c.expectedTeamID, client.TeamID)
+		}
+	}
+}
+
+func TestConfigClient_clientCredentials(t *testing.T) {
+	server := testAccountServer("oauth-token")
+	defer server.Close()
+
+	cases := []struct {
+		name         string
+		accessToken  string
+		clientID     string
+		clientSecret string
+		expectErr    string
+	}{
+		{"client credentials", "", "client-id", "client-secret", ""},
+		{"access token first", "personal-token", "client-id", "client-secret", "Invalid runscope access_token"},
+		{"invalid secret", "", "client-id", "wrong-secret", "401 Unauthorized invalid_client unknown client"},
+		{"missing secret", "", "client-id", "", "Both client_id and client_secret must be set"},
+	}
+
+	for _, c := range cases {
+		config := config{
+			AccessToken:   c.accessToken,
+			ClientID:      c.clientID,
+			ClientSecret:  c.clientSecret,
+			OAuthTokenURL: server.URL + "/oauth/token",
+			APIURL:        server.URL,
+		}
+
+		_, err := config.client()
+		if c.expectErr == "" && err != nil {
+			t.Fatalf("%s: unexpected error %s", c.name, err)
+		}
+
+		if c.expectErr != "" && (err == nil || !strings.Contains(err.Error(), c.expectErr)) {
+			t.Fatalf("%s: expected an error containing %q, actual %v", c.name, c.expectErr, err)
+		}
+
+		if err != nil && strings.Contains(err.Error(), c.clientSecret) && c.clientSecret != "" {
+			t.Fatalf("%s: expected the client secret to be left out of the error: %s", c.name, err)
+		}
+	}
+}
+
+func TestConfigClient_credentialsRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.PostForm.Get("client_id") {
+		case "rejected":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "invalid_client", "error_description": "unknown client"}`))
+		case "unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"token_type": "bearer"}`))
+		}
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		clientID  string
+		rejected  bool
+		expectErr string
+	}{
+		{"rejected", true, "client_id rejected were rejected by " + server.URL + ": 401 Unauthorized invalid_client unknown client"},
+		{"unavailable", false, "503 Service Unavailable"},
+		{"no-token", false, "no access_token in the response"},
+	}
+
+	for _, c := range cases {
+		config := config{
+			ClientID:      c.clientID,
+			ClientSecret:  "client-secret",
+			OAuthTokenURL: server.URL,
+			APIURL:        server.URL,
+		}
+
+		_, err := config.client()
+		if err == nil || !strings.Contains(err.Error(), c.expectErr) {
+			t.Fatalf("%s: expected an error containing %q, actual %v", c.clientID, c.expectErr, err)
+		}
+
+		if isCredentialsRejected(err) != c.rejected {
+			t.Fatalf("%s: expected rejected credentials to be %t, actual %T", c.clientID, c.rejected, err)
+		}
+	}
+}
+
+func TestConfigCredentialsFromEnvironment(t *testing.T) {
+	environment := map[string]string{
+		"RUNSCOPE_ACCESS_TOKEN":  "env-token",
+		"RUNSCOPE_CLIENT_ID":     "env-client-id",
+		"RUNSCOPE_CLIENT_SECRET": "env-client-secret",
+		"RUNSCOPE_PROFILE":       "env-profile",
+	}
+
+	for name, value := range environment {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Setenv(name, value)
+	}
+
+	cases := []struct {
+		name     string
+		config   config
+		expected config
+	}{
+		{"environment", config{},
+			config{AccessToken: "env-token", ClientID: "env-client-id", ClientSecret: "env-client-secret", Profile: "env-profile"}},
+		{"access token", config{AccessToken: "token"},
+			config{AccessToken: "token", ClientID: "env-client-id", ClientSecret: "env-client-secret", Profile: "env-profile"}},
+		{"client id", config{ClientID: "client-id"},
+			config{AccessToken: "env-token", ClientID: "client-id", ClientSecret: "env-client-secret", Profile: "env-profile"}},
+		{"profile", config{Profile: "profile"}, config{Profile: "profile"}},
+	}
+
+	for _, c := range cases {
+		c.config.credentialsFromEnvironment()
+		if c.config != c.expected {
+			t.Fatalf("%s: expected %+v, actual %+v", c.name, c.expected, c.config)
+		}
+	}
+}
+
+func TestProviderConfigure_profileOverEnvironment(t *testing.T) {
+	server := testAccountServer("profile-token")
+	defer server.Close()
+
+	credentials, err := ioutil.TempFile("", "runscope-credentials")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(credentials.Name())
+
+	credentials.WriteString(`[other-team]
+access_token = profile-token
+team_uuid    = other-team
+`)
+	credentials.Close()
+
+	defer os.Setenv("RUNSCOPE_ACCESS_TOKEN", os.Getenv("RUNSCOPE_ACCESS_TOKEN"))
+	defer os.Setenv("RUNSCOPE_TEAM_ID", os.Getenv("RUNSCOPE_TEAM_ID"))
+	os.Setenv("RUNSCOPE_ACCESS_TOKEN", "env-token")
+	os.Unsetenv("RUNSCOPE_TEAM_ID")
+
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"api_url":          server.URL,
+		"credentials_file": credentials.Name(),
+		"profile":          "other-team",
+	})
+
+	meta, err := providerConfigure(d, context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	if client := meta.(*runscopeClient); client.TeamID != "other-team" {
+		t.Fatalf("expected team %q from the profile, actual %q", "other-team", client.TeamID)
+	}
+}
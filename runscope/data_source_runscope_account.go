@@ -0,0 +1,84 @@
+package runscope
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ewilde/go-runscope"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceRunscopeAccount() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRunscopeAccountRead,
+
+		Schema: map[string]*schema.Schema{
+			"uuid": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"email": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"teams": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRunscopeAccountRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(dataSourceReadTimeout)
+	defer cancel()
+
+	log.Printf("[INFO] Reading Runscope account")
+
+	account, err := client.ReadAccount(ctx)
+	if err != nil {
+		return fmt.Errorf("Error reading account: %s", err)
+	}
+
+	d.SetId(account.ID)
+	d.Set("uuid", account.UUID)
+	d.Set("name", account.Name)
+	d.Set("email", account.Email)
+	d.Set("created_at", formatTime(&account.CreatedAt))
+	d.Set("teams", readAccountTeams(account.Teams))
+
+	return nil
+}
+
+func readAccountTeams(teams []*runscope.AccountTeam) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(teams))
+	for _, team := range teams {
+		result = append(result, map[string]interface{}{
+			"uuid": team.UUID,
+			"name": team.Name,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,54 @@
+package runscope
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccDataSourceRunscopeAccount_Basic(t *testing.T) {
+
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceRunscopeAccountConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.runscope_account.account", "id"),
+					resource.TestCheckResourceAttrSet("data.runscope_account.account", "email"),
+					resource.TestCheckResourceAttrSet("data.runscope_account.account", "teams.#"),
+					testAccCheckAccountTeam("data.runscope_account.account", teamID),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckAccountTeam(n string, teamID string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		count, _ := strconv.Atoi(rs.Primary.Attributes["teams.#"])
+		for i := 0; i < count; i++ {
+			if rs.Primary.Attributes[fmt.Sprintf("teams.%d.uuid", i)] == teamID {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Expected account to be a member of team %s", teamID)
+	}
+}
+
+const testAccDataSourceRunscopeAccountConfig = `
+data "runscope_account" "account" {}
+`
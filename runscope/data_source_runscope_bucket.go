@@ -0,0 +1,106 @@
+package runscope
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ewilde/go-runscope"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceRunscopeBucket() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRunscopeBucketRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"team_uuid": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"key": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"team_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"default": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"verify_ssl": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"trigger_url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"tests_url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceRunscopeBucketRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(dataSourceReadTimeout)
+	defer cancel()
+
+	name := d.Get("name").(string)
+	teamID, err := client.teamID(d)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Reading Runscope bucket name: %s team: %s", name, teamID)
+
+	buckets, err := client.ListBuckets(ctx)
+	if err != nil {
+		return fmt.Errorf("Error listing buckets: %s", err)
+	}
+
+	var found []*runscope.Bucket
+	for _, bucket := range buckets {
+		if bucket.Name != name {
+			continue
+		}
+
+		if bucket.Team == nil || bucket.Team.ID != teamID {
+			continue
+		}
+
+		found = append(found, bucket)
+	}
+
+	if len(found) == 0 {
+		return fmt.Errorf("Unable to locate any buckets with the name: %s in team: %s", name, teamID)
+	}
+
+	if len(found) > 1 {
+		return fmt.Errorf("Found %d buckets with the name: %s in team: %s", len(found), name, teamID)
+	}
+
+	bucket := found[0]
+	d.SetId(bucket.Key)
+	d.Set("key", bucket.Key)
+	d.Set("name", bucket.Name)
+	d.Set("default", bucket.Default)
+	d.Set("verify_ssl", bucket.VerifySsl)
+	d.Set("trigger_url", bucket.TriggerURL)
+	d.Set("tests_url", bucket.TestsURL)
+	if bucket.Team != nil {
+		d.Set("team_uuid", bucket.Team.ID)
+		d.Set("team_name", bucket.Team.Name)
+	}
+
+	return nil
+}
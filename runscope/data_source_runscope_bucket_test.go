@@ -0,0 +1,92 @@
+package runscope
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceRunscopeBucket_Basic(t *testing.T) {
+
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceRunscopeBucketConfig, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.runscope_bucket.by_name", "key", "runscope_bucket.bucket", "id"),
+					resource.TestCheckResourceAttr("data.runscope_bucket.by_name", "team_uuid", teamID),
+					resource.TestCheckResourceAttrSet("data.runscope_bucket.by_name", "trigger_url"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceRunscopeBucket_team(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	otherTeamID := os.Getenv("RUNSCOPE_OTHER_TEAM_ID")
+	if otherTeamID == "" {
+		t.Skip("RUNSCOPE_OTHER_TEAM_ID must be set to a second team of the account for this acceptance test")
+	}
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				// both buckets are created before they are looked up
+				Config: fmt.Sprintf(testAccDataSourceRunscopeBucketTeamConfigBuckets, teamID, otherTeamID),
+			},
+			{
+				Config: fmt.Sprintf(testAccDataSourceRunscopeBucketTeamConfig, teamID, otherTeamID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.runscope_bucket.provider_team", "key", "runscope_bucket.bucket", "id"),
+					resource.TestCheckResourceAttr("data.runscope_bucket.provider_team", "team_uuid", teamID),
+					resource.TestCheckResourceAttrPair("data.runscope_bucket.other_team", "key", "runscope_bucket.other", "id"),
+					resource.TestCheckResourceAttr("data.runscope_bucket.other_team", "team_uuid", otherTeamID),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceRunscopeBucketConfig = `
+resource "runscope_bucket" "bucket" {
+	name = "terraform-provider-data-source-test"
+	team_uuid = "%[1]s"
+}
+
+data "runscope_bucket" "by_name" {
+	name = "${runscope_bucket.bucket.name}"
+	team_uuid = "%[1]s"
+}
+`
+
+const testAccDataSourceRunscopeBucketTeamConfigBuckets = `
+resource "runscope_bucket" "bucket" {
+	name = "terraform-provider-data-source-team-test"
+	team_uuid = "%[1]s"
+}
+
+resource "runscope_bucket" "other" {
+	name = "terraform-provider-data-source-team-test"
+	team_uuid = "%[2]s"
+}
+`
+
+const testAccDataSourceRunscopeBucketTeamConfig = testAccDataSourceRunscopeBucketTeamConfigBuckets + `
+data "runscope_bucket" "provider_team" {
+	name = "${runscope_bucket.bucket.name}"
+}
+
+data "runscope_bucket" "other_team" {
+	name = "${runscope_bucket.other.name}"
+	team_uuid = "%[2]s"
+}
+`
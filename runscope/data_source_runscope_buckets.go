@@ -0,0 +1,78 @@
+package runscope
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceRunscopeBuckets() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRunscopeBucketsRead,
+
+		Schema: map[string]*schema.Schema{
+			"team_uuid": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"filter": dataSourceFiltersSchema(),
+			"keys": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceRunscopeBucketsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(dataSourceReadTimeout)
+	defer cancel()
+
+	teamID, err := client.teamID(d)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Reading Runscope buckets team: %s", teamID)
+
+	filters, filtersOk := d.GetOk("filter")
+
+	buckets, err := client.ListBuckets(ctx)
+	if err != nil {
+		return fmt.Errorf("Error listing buckets: %s", err)
+	}
+
+	keys := []string{}
+	for _, bucket := range buckets {
+		if bucket.Team == nil || bucket.Team.ID != teamID {
+			continue
+		}
+
+		if filtersOk {
+			fields := map[string]string{"key": bucket.Key, "name": bucket.Name, "team_uuid": ""}
+			if bucket.Team != nil {
+				fields["team_uuid"] = bucket.Team.ID
+			}
+
+			passed, err := filtersTest(fields, filters.(*schema.Set))
+			if err != nil {
+				return err
+			}
+
+			if !passed {
+				continue
+			}
+		}
+
+		keys = append(keys, bucket.Key)
+	}
+
+	d.SetId(time.Now().UTC().String())
+	d.Set("keys", keys)
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package runscope
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceRunscopeBuckets_Basic(t *testing.T) {
+
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceRunscopeBucketsConfig, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.runscope_buckets.by_name", "keys.#", "1"),
+					resource.TestCheckResourceAttrPair("data.runscope_buckets.by_name", "keys.0", "runscope_bucket.bucket", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceRunscopeBuckets_team(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	otherTeamID := os.Getenv("RUNSCOPE_OTHER_TEAM_ID")
+	if otherTeamID == "" {
+		t.Skip("RUNSCOPE_OTHER_TEAM_ID must be set to a second team of the account for this acceptance test")
+	}
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				// both buckets are created before they are looked up
+				Config: fmt.Sprintf(testAccDataSourceRunscopeBucketsTeamConfigBuckets, teamID, otherTeamID),
+			},
+			{
+				Config: fmt.Sprintf(testAccDataSourceRunscopeBucketsTeamConfig, teamID, otherTeamID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.runscope_buckets.provider_team", "keys.#", "1"),
+					resource.TestCheckResourceAttrPair("data.runscope_buckets.provider_team", "keys.0", "runscope_bucket.bucket", "id"),
+					resource.TestCheckResourceAttr("data.runscope_buckets.other_team", "keys.#", "1"),
+					resource.TestCheckResourceAttrPair("data.runscope_buckets.other_team", "keys.0", "runscope_bucket.other", "id"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceRunscopeBucketsConfig = `
+resource "runscope_bucket" "bucket" {
+	name = "terraform-provider-data-source-test"
+	team_uuid = "%[1]s"
+}
+
+data "runscope_buckets" "by_name" {
+	filter = {
+		name = "name"
+		values = ["${runscope_bucket.bucket.name}"]
+	}
+
+	filter = {
+		name = "team_uuid"
+		values = ["%[1]s"]
+	}
+}
+`
+
+const testAccDataSourceRunscopeBucketsTeamConfigBuckets = `
+resource "runscope_bucket" "bucket" {
+	name = "terraform-provider-data-source-team-test"
+	team_uuid = "%[1]s"
+}
+
+resource "runscope_bucket" "other" {
+	name = "terraform-provider-data-source-team-test"
+	team_uuid = "%[2]s"
+}
+`
+
+const testAccDataSourceRunscopeBucketsTeamConfig = testAccDataSourceRunscopeBucketsTeamConfigBuckets + `
+data "runscope_buckets" "provider_team" {
+	filter = {
+		name = "name"
+		values = ["${runscope_bucket.bucket.name}"]
+	}
+}
+
+data "runscope_buckets" "other_team" {
+	team_uuid = "%[2]s"
+
+	filter = {
+		name = "name"
+		values = ["${runscope_bucket.other.name}"]
+	}
+}
+`
@@ -0,0 +1,112 @@
+package runscope
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ewilde/go-runscope"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceRunscopeEnvironment() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRunscopeEnvironmentRead,
+
+		Schema: map[string]*schema.Schema{
+			"bucket_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"script": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"preserve_cookies": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"initial_variables": {
+				Type:      schema.TypeMap,
+				Computed:  true,
+				Sensitive: true,
+				Elem:      &schema.Schema{Type: schema.TypeString},
+			},
+			"integrations": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"regions": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"retry_on_failure": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"verify_ssl": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"webhooks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"parent_environment_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceRunscopeEnvironmentRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(dataSourceReadTimeout)
+	defer cancel()
+
+	bucketID := d.Get("bucket_id").(string)
+	name := d.Get("name").(string)
+	log.Printf("[INFO] Reading Runscope shared environment name: %s bucket: %s", name, bucketID)
+
+	environments, err := client.ListSharedEnvironment(ctx, &runscope.Bucket{Key: bucketID})
+	if err != nil {
+		return fmt.Errorf("Error listing shared environments: %s", err)
+	}
+
+	var found []*runscope.Environment
+	for _, environment := range environments {
+		if environment.Name == name {
+			found = append(found, environment)
+		}
+	}
+
+	if len(found) == 0 {
+		return fmt.Errorf("Unable to locate any shared environments with the name: %s", name)
+	}
+
+	if len(found) > 1 {
+		return fmt.Errorf("Found %d shared environments with the name: %s", len(found), name)
+	}
+
+	environment := found[0]
+	d.SetId(environment.ID)
+	d.Set("name", environment.Name)
+	d.Set("script", environment.Script)
+	d.Set("preserve_cookies", environment.PreserveCookies)
+	d.Set("initial_variables", environment.InitialVariables)
+	d.Set("integrations", readIntegrations(environment.Integrations))
+	d.Set("regions", environment.Regions)
+	d.Set("retry_on_failure", environment.RetryOnFailure)
+	d.Set("verify_ssl", environment.VerifySsl)
+	d.Set("webhooks", environment.WebHooks)
+	d.Set("parent_environment_id", environment.ParentEnvironmentID)
+
+	return nil
+}
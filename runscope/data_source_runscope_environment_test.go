@@ -0,0 +1,63 @@
+package runscope
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceRunscopeEnvironment_Basic(t *testing.T) {
+
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceRunscopeEnvironmentResourcesConfig, teamID),
+			},
+			{
+				Config: fmt.Sprintf(testAccDataSourceRunscopeEnvironmentConfig, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.runscope_environment.by_name", "id", "runscope_environment.environment", "id"),
+					resource.TestCheckResourceAttr("data.runscope_environment.by_name", "initial_variables.var1", "true"),
+					resource.TestCheckResourceAttr("data.runscope_environment.by_name", "regions.#", "2"),
+					resource.TestCheckResourceAttr("data.runscope_environment.by_name", "verify_ssl", "false"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceRunscopeEnvironmentResourcesConfig = `
+resource "runscope_bucket" "bucket" {
+	name = "terraform-provider-data-source-test"
+	team_uuid = "%s"
+}
+
+resource "runscope_environment" "environment" {
+	bucket_id = "${runscope_bucket.bucket.id}"
+	name = "shared-environment"
+	regions = ["us1", "eu1"]
+	verify_ssl = false
+
+	initial_variables {
+		var1 = "true"
+	}
+}
+
+resource "runscope_environment" "other" {
+	bucket_id = "${runscope_bucket.bucket.id}"
+	name = "other-environment"
+}
+`
+
+const testAccDataSourceRunscopeEnvironmentConfig = testAccDataSourceRunscopeEnvironmentResourcesConfig + `
+data "runscope_environment" "by_name" {
+	bucket_id = "${runscope_bucket.bucket.id}"
+	name = "${runscope_environment.environment.name}"
+}
+`
@@ -15,7 +15,8 @@ func dataSourceRunscopeIntegration() *schema.Resource {
 		Schema: map[string]*schema.Schema{
 			"team_uuid": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
 			"filter": &schema.Schema{
 				Type:     schema.TypeSet,
@@ -48,14 +49,21 @@ func dataSourceRunscopeIntegration() *schema.Resource {
 }
 
 func dataSourceRunscopeIntegrationRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(dataSourceReadTimeout)
+	defer cancel()
 
 	log.Printf("[INFO] Reading Runscope integration")
 
 	searchType := d.Get("type").(string)
 	filters, filtersOk := d.GetOk("filter")
 
-	resp, err := client.ListIntegrations(d.Get("team_uuid").(string))
+	teamID, err := client.teamID(d)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.ListIntegrations(ctx, teamID)
 	if err != nil {
 		return err
 	}
@@ -79,6 +87,7 @@ func dataSourceRunscopeIntegrationRead(d *schema.ResourceData, meta interface{})
 
 	d.SetId(found.ID)
 	d.Set("id", found.ID)
+	d.Set("team_uuid", teamID)
 	d.Set("type", found.IntegrationType)
 	d.Set("description", found.Description)
 
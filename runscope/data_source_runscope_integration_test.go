@@ -13,7 +13,7 @@ func TestAccDataSourceRunscopeIntegration_Basic(t *testing.T) {
 
 	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
 
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:  func() { testAccPreCheck(t) },
 		Providers: testAccProviders,
 		Steps: []resource.TestStep{
@@ -63,7 +63,7 @@ func TestAccDataSourceRunscopeIntegration_Filter(t *testing.T) {
 		t.Fatal("RUNSCOPE_INTEGRATION_DESC must be set for this acceptance tests")
 	}
 
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:  func() { testAccPreCheck(t) },
 		Providers: testAccProviders,
 		Steps: []resource.TestStep{
@@ -4,7 +4,6 @@ import (
 	"log"
 	"time"
 
-	"github.com/ewilde/go-runscope"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -15,7 +14,8 @@ func dataSourceRunscopeIntegrations() *schema.Resource {
 		Schema: map[string]*schema.Schema{
 			"team_uuid": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
 			"filter": &schema.Schema{
 				Type:     schema.TypeSet,
@@ -45,13 +45,20 @@ func dataSourceRunscopeIntegrations() *schema.Resource {
 }
 
 func dataSourceRunscopeIntegrationsRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(dataSourceReadTimeout)
+	defer cancel()
 
 	log.Printf("[INFO] Reading Runscope integration")
 
 	filters, filtersOk := d.GetOk("filter")
 
-	resp, err := client.ListIntegrations(d.Get("team_uuid").(string))
+	teamID, err := client.teamID(d)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.ListIntegrations(ctx, teamID)
 	if err != nil {
 		return err
 	}
@@ -68,6 +75,7 @@ func dataSourceRunscopeIntegrationsRead(d *schema.ResourceData, meta interface{}
 	}
 
 	d.SetId(time.Now().UTC().String())
+	d.Set("team_uuid", teamID)
 	d.Set("ids", ids)
 
 	return nil
@@ -1,6 +1,7 @@
 package runscope
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -14,7 +15,7 @@ func TestAccDataSourceRunscopeIntegrations_Basic(t *testing.T) {
 
 	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
 
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:  func() { testAccPreCheck(t) },
 		Providers: testAccProviders,
 		Steps: []resource.TestStep{
@@ -55,7 +56,7 @@ func TestAccDataSourceRunscopeIntegrations_usage(t *testing.T) {
 
 	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
 
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:  func() { testAccPreCheck(t) },
 		Providers: testAccProviders,
 		Steps: []resource.TestStep{
@@ -82,7 +83,7 @@ func testAccCheckEnvironmentIntegrations(environment string, expected bool) reso
 			return fmt.Errorf("No Record ID is set")
 		}
 
-		client := testAccProvider.Meta().(*runscope.Client)
+		client := testAccProvider.Meta().(*runscopeClient)
 
 		var foundRecord *runscope.Environment
 		var err error
@@ -90,7 +91,7 @@ func testAccCheckEnvironmentIntegrations(environment string, expected bool) reso
 		environment := new(runscope.Environment)
 		environment.ID = rs.Primary.ID
 		bucketID := rs.Primary.Attributes["bucket_id"]
-		foundRecord, err = client.ReadSharedEnvironment(environment,
+		foundRecord, err = client.ReadSharedEnvironment(context.Background(), environment,
 			&runscope.Bucket{Key: bucketID})
 
 		if err != nil {
@@ -0,0 +1,111 @@
+package runscope
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceRunscopePeople() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRunscopePeopleRead,
+
+		Schema: map[string]*schema.Schema{
+			"team_uuid": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"filter": dataSourceFiltersSchema(),
+			"people": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"email": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"group": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"last_login_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRunscopePeopleRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(dataSourceReadTimeout)
+	defer cancel()
+
+	teamID, err := client.teamID(d)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[INFO] Reading Runscope people for team: %s", teamID)
+
+	filters, filtersOk := d.GetOk("filter")
+
+	people, err := client.ListPeople(ctx, teamID)
+	if err != nil {
+		return fmt.Errorf("Error listing people: %s", err)
+	}
+
+	result := []map[string]interface{}{}
+	for _, person := range people {
+		if filtersOk {
+			fields := map[string]string{"id": person.ID, "name": person.Name, "email": person.Email, "group": person.GroupName}
+			passed, err := filtersTest(fields, filters.(*schema.Set))
+			if err != nil {
+				return err
+			}
+
+			if !passed {
+				continue
+			}
+		}
+
+		result = append(result, map[string]interface{}{
+			"id":            person.ID,
+			"uuid":          person.UUID,
+			"name":          person.Name,
+			"email":         person.Email,
+			"group":         person.GroupName,
+			"created_at":    formatTime(&person.CreatedAt),
+			"last_login_at": formatTime(&person.LastLoginAt),
+		})
+	}
+
+	d.SetId(time.Now().UTC().String())
+	d.Set("team_uuid", teamID)
+	d.Set("people", result)
+
+	return nil
+}
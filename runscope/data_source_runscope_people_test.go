@@ -0,0 +1,50 @@
+package runscope
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceRunscopePeople_Basic(t *testing.T) {
+
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	email := os.Getenv("RUNSCOPE_PERSON_EMAIL")
+	if email == "" {
+		t.Skip("RUNSCOPE_PERSON_EMAIL must be set to the email of a person in the team for this acceptance test")
+	}
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceRunscopePeopleConfig, teamID, email),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.runscope_people.all", "people.#"),
+					resource.TestCheckResourceAttr("data.runscope_people.by_email", "people.#", "1"),
+					resource.TestCheckResourceAttrSet("data.runscope_people.by_email", "people.0.id"),
+					resource.TestCheckResourceAttrSet("data.runscope_people.by_email", "people.0.name"),
+					resource.TestCheckResourceAttrSet("data.runscope_people.by_email", "people.0.group"),
+					resource.TestCheckResourceAttr("data.runscope_people.by_email", "people.0.email", email),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceRunscopePeopleConfig = `
+data "runscope_people" "all" {
+	team_uuid = "%[1]s"
+}
+
+data "runscope_people" "by_email" {
+	team_uuid = "%[1]s"
+	filter = {
+		name = "email"
+		values = ["%[2]s"]
+	}
+}
+`
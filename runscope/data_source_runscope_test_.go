@@ -0,0 +1,202 @@
+package runscope
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ewilde/go-runscope"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceRunscopeTest() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRunscopeTestRead,
+
+		Schema: map[string]*schema.Schema{
+			"bucket_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"default_environment_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"trigger_url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"step_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"environments": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"last_run": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"environment_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"environment_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"region": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"assertion_count": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"assertion_success": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"finished_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRunscopeTestRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(dataSourceReadTimeout)
+	defer cancel()
+
+	bucketID := d.Get("bucket_id").(string)
+	name := d.Get("name").(string)
+	log.Printf("[INFO] Reading Runscope test name: %s bucket: %s", name, bucketID)
+
+	tests, err := client.ReadTests(ctx, bucketID)
+	if err != nil {
+		return fmt.Errorf("Error listing tests: %s", err)
+	}
+
+	var found []*runscope.Test
+	for _, test := range tests {
+		if test.Name == name {
+			found = append(found, test)
+		}
+	}
+
+	if len(found) == 0 {
+		return fmt.Errorf("Unable to locate any tests with the name: %s", name)
+	}
+
+	if len(found) > 1 {
+		return fmt.Errorf("Found %d tests with the name: %s", len(found), name)
+	}
+
+	// the test list does not include steps, so read the details of the match
+	test, err := client.ReadTest(ctx, &runscope.Test{ID: found[0].ID, Bucket: &runscope.Bucket{Key: bucketID}})
+	if err != nil {
+		return fmt.Errorf("Error reading test %s: %s", found[0].ID, err)
+	}
+
+	d.SetId(test.ID)
+	d.Set("name", test.Name)
+	d.Set("description", test.Description)
+	d.Set("default_environment_id", test.DefaultEnvironmentID)
+	d.Set("trigger_url", test.TriggerURL)
+	d.Set("created_at", formatTime(test.CreatedAt))
+
+	stepIDs := make([]string, 0, len(test.Steps))
+	for _, step := range test.Steps {
+		stepIDs = append(stepIDs, step.ID)
+	}
+
+	d.Set("step_ids", stepIDs)
+	d.Set("environments", readTestEnvironments(test.Environments))
+	d.Set("last_run", readLastRun(test.LastRun))
+
+	return nil
+}
+
+func readTestEnvironments(environments []*runscope.Environment) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(environments))
+	for _, environment := range environments {
+		result = append(result, map[string]interface{}{
+			"id":   environment.ID,
+			"name": environment.Name,
+		})
+	}
+
+	return result
+}
+
+func readLastRun(lastRun *runscope.TestRun) []map[string]interface{} {
+	if lastRun == nil {
+		return []map[string]interface{}{}
+	}
+
+	return []map[string]interface{}{
+		{
+			"id":                lastRun.ID,
+			"status":            lastRun.Status,
+			"environment_id":    lastRun.EnvironmentUUID,
+			"environment_name":  lastRun.EnvironmentName,
+			"region":            lastRun.Region,
+			"assertion_count":   lastRun.AssertionCount,
+			"assertion_success": lastRun.AssertionSuccess,
+			"created_at":        formatTime(lastRun.CreatedAt),
+			"finished_at":       formatTime(lastRun.FinishedAt),
+		},
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,64 @@
+package runscope
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceRunscopeTest_Basic(t *testing.T) {
+
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceRunscopeTestResourcesConfig, teamID),
+			},
+			{
+				Config: fmt.Sprintf(testAccDataSourceRunscopeTestConfig, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.runscope_test.by_name", "id", "runscope_test.test", "id"),
+					resource.TestCheckResourceAttr("data.runscope_test.by_name", "description", "Data source test"),
+					resource.TestCheckResourceAttrPair("data.runscope_test.by_name", "default_environment_id", "runscope_test.test", "default_environment_id"),
+					resource.TestCheckResourceAttr("data.runscope_test.by_name", "step_ids.#", "1"),
+					resource.TestCheckResourceAttrPair("data.runscope_test.by_name", "step_ids.0", "runscope_step.step", "id"),
+					resource.TestCheckResourceAttr("data.runscope_test.by_name", "environments.#", "1"),
+					resource.TestCheckResourceAttrSet("data.runscope_test.by_name", "trigger_url"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceRunscopeTestResourcesConfig = `
+resource "runscope_bucket" "bucket" {
+	name = "terraform-provider-data-source-test"
+	team_uuid = "%s"
+}
+
+resource "runscope_test" "test" {
+	bucket_id = "${runscope_bucket.bucket.id}"
+	name = "runscope test"
+	description = "Data source test"
+}
+
+resource "runscope_step" "step" {
+	bucket_id = "${runscope_bucket.bucket.id}"
+	test_id = "${runscope_test.test.id}"
+	step_type = "request"
+	url = "http://example.com"
+	method = "GET"
+}
+`
+
+const testAccDataSourceRunscopeTestConfig = testAccDataSourceRunscopeTestResourcesConfig + `
+data "runscope_test" "by_name" {
+	bucket_id = "${runscope_bucket.bucket.id}"
+	name = "${runscope_test.test.name}"
+}
+`
@@ -0,0 +1,66 @@
+package runscope
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceRunscopeTests() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRunscopeTestsRead,
+
+		Schema: map[string]*schema.Schema{
+			"bucket_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"filter": dataSourceFiltersSchema(),
+			"ids": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceRunscopeTestsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(dataSourceReadTimeout)
+	defer cancel()
+
+	bucketID := d.Get("bucket_id").(string)
+	log.Printf("[INFO] Reading Runscope tests for bucket: %s", bucketID)
+
+	filters, filtersOk := d.GetOk("filter")
+
+	tests, err := client.ReadTests(ctx, bucketID)
+	if err != nil {
+		return fmt.Errorf("Error listing tests: %s", err)
+	}
+
+	ids := []string{}
+	for _, test := range tests {
+		if filtersOk {
+			fields := map[string]string{"id": test.ID, "name": test.Name, "description": test.Description}
+			passed, err := filtersTest(fields, filters.(*schema.Set))
+			if err != nil {
+				return err
+			}
+
+			if !passed {
+				continue
+			}
+		}
+
+		ids = append(ids, test.ID)
+	}
+
+	d.SetId(time.Now().UTC().String())
+	d.Set("ids", ids)
+
+	return nil
+}
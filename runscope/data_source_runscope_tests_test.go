@@ -0,0 +1,65 @@
+package runscope
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceRunscopeTests_Basic(t *testing.T) {
+
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceRunscopeTestsResourcesConfig, teamID),
+			},
+			{
+				Config: fmt.Sprintf(testAccDataSourceRunscopeTestsConfig, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.runscope_tests.all", "ids.#", "2"),
+					resource.TestCheckResourceAttr("data.runscope_tests.by_name", "ids.#", "1"),
+					resource.TestCheckResourceAttrPair("data.runscope_tests.by_name", "ids.0", "runscope_test.test_b", "id"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceRunscopeTestsResourcesConfig = `
+resource "runscope_bucket" "bucket" {
+	name = "terraform-provider-data-source-test"
+	team_uuid = "%s"
+}
+
+resource "runscope_test" "test_a" {
+	bucket_id = "${runscope_bucket.bucket.id}"
+	name = "runscope test a"
+	description = "Data source test"
+}
+
+resource "runscope_test" "test_b" {
+	bucket_id = "${runscope_bucket.bucket.id}"
+	name = "runscope test b"
+	description = "Data source test"
+}
+`
+
+const testAccDataSourceRunscopeTestsConfig = testAccDataSourceRunscopeTestsResourcesConfig + `
+data "runscope_tests" "all" {
+	bucket_id = "${runscope_bucket.bucket.id}"
+}
+
+data "runscope_tests" "by_name" {
+	bucket_id = "${runscope_bucket.bucket.id}"
+	filter = {
+		name = "name"
+		values = ["${runscope_test.test_b.name}"]
+	}
+}
+`
@@ -1,32 +1,115 @@
 package runscope
 
 import (
+	"context"
+	"time"
+
+	"github.com/ewilde/go-runscope"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
 
+// defaultRequestTimeout is the default for how long a single api request may take
+const defaultRequestTimeout = 60 * time.Second
+
 // Provider returns a terraform.ResourceProvider.
 func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"access_token": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("RUNSCOPE_ACCESS_TOKEN", nil),
-				Description: "A runscope access token.",
+				Optional:    true,
+				Sensitive:   true,
+				Description: "A runscope access token, takes precedence over client_id and client_secret and the profile.",
+			},
+			"client_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The client id of a runscope oauth application, exchanged for an access token with client_secret.",
+			},
+			"client_secret": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The client secret of a runscope oauth application.",
+			},
+			"oauth_token_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("RUNSCOPE_OAUTH_TOKEN_URL", defaultOAuthTokenURL),
+				Description: "The url client_id and client_secret are exchanged for an access token at.",
+			},
+			"credentials_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("RUNSCOPE_CREDENTIALS_FILE", defaultCredentialsFile),
+				Description: "The credentials file profiles are read from when no access_token or client_id is set.",
+			},
+			"profile": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The profile in the credentials file to use, takes precedence over credentials in the environment.",
 			},
 			"api_url": &schema.Schema{
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("RUNSCOPE_API_URL", nil),
+				DefaultFunc: schema.EnvDefaultFunc("RUNSCOPE_API_URL", "https://api.runscope.com"),
 				Description: "A runscope api url i.e. https://api.runscope.com.",
-				Default:     "https://api.runscope.com",
+			},
+			"team_uuid": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("RUNSCOPE_TEAM_ID", nil),
+				Description: "The default team used by resources and data sources that do not set their own team_uuid.",
+			},
+			"max_retries": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     runscope.DefaultMaxRetries,
+				Description: "The maximum number of times a rate limited or failed request is retried.",
+			},
+			"retry_max_wait": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     int(runscope.DefaultRetryMaxWait / time.Second),
+				Description: "The maximum number of seconds to wait between retries.",
+			},
+			"request_timeout": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     int(defaultRequestTimeout / time.Second),
+				Description: "The maximum number of seconds a single request to the runscope api may take.",
+			},
+			"proxy_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("RUNSCOPE_PROXY_URL", ""),
+				Description: "The url of a proxy used to reach the runscope api, overriding HTTP_PROXY and HTTPS_PROXY.",
+			},
+			"ca_bundle": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("RUNSCOPE_CA_BUNDLE", ""),
+				Description: "A PEM encoded certificate bundle, or the path to one, trusted in addition to the system roots.",
+			},
+			"insecure_skip_verify": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Skip verification of the runscope api tls certificate, only intended for local test servers.",
 			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
+			"runscope_account":      dataSourceRunscopeAccount(),
+			"runscope_bucket":       dataSourceRunscopeBucket(),
+			"runscope_buckets":      dataSourceRunscopeBuckets(),
+			"runscope_environment":  dataSourceRunscopeEnvironment(),
 			"runscope_integration":  dataSourceRunscopeIntegration(),
 			"runscope_integrations": dataSourceRunscopeIntegrations(),
+			"runscope_people":       dataSourceRunscopePeople(),
+			"runscope_test":         dataSourceRunscopeTest(),
+			"runscope_tests":        dataSourceRunscopeTests(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -35,16 +118,38 @@ func Provider() terraform.ResourceProvider {
 			"runscope_environment": resourceRunscopeEnvironment(),
 			"runscope_schedule":    resourceRunscopeSchedule(),
 			"runscope_step":        resourceRunscopeStep(),
+			"runscope_test_run":    resourceRunscopeTestRun(),
 		},
+	}
 
-		ConfigureFunc: providerConfigure,
+	// the stop context is only available once the provider exists, it lets an
+	// interrupt cancel requests that are waiting on the runscope api
+	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return providerConfigure(d, provider.StopContext())
 	}
+
+	return provider
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(d *schema.ResourceData, stopCtx context.Context) (interface{}, error) {
 	config := config{
-		AccessToken: d.Get("access_token").(string),
-		APIURL:      d.Get("api_url").(string),
+		AccessToken:        d.Get("access_token").(string),
+		ClientID:           d.Get("client_id").(string),
+		ClientSecret:       d.Get("client_secret").(string),
+		OAuthTokenURL:      d.Get("oauth_token_url").(string),
+		CredentialsFile:    d.Get("credentials_file").(string),
+		Profile:            d.Get("profile").(string),
+		APIURL:             d.Get("api_url").(string),
+		MaxRetries:         d.Get("max_retries").(int),
+		RetryMaxWait:       time.Duration(d.Get("retry_max_wait").(int)) * time.Second,
+		RequestTimeout:     time.Duration(d.Get("request_timeout").(int)) * time.Second,
+		TeamID:             d.Get("team_uuid").(string),
+		ProxyURL:           d.Get("proxy_url").(string),
+		CABundle:           d.Get("ca_bundle").(string),
+		InsecureSkipVerify: d.Get("insecure_skip_verify").(bool),
+		StopContext:        stopCtx,
 	}
+	config.credentialsFromEnvironment()
+
 	return config.client()
 }
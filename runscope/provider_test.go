@@ -1,9 +1,15 @@
 package runscope
 
 import (
+	"fmt"
 	"os"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ewilde/terraform-provider-runscope/runscopetest"
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -11,6 +17,10 @@ import (
 var testAccProviders map[string]terraform.ResourceProvider
 var testAccProvider *schema.Provider
 
+// testAccServer is the mock runscope api the acceptance tests run against
+// when no RUNSCOPE_ACCESS_TOKEN is set
+var testAccServer *runscopetest.Server
+
 func init() {
 	testAccProvider = Provider().(*schema.Provider)
 	testAccProviders = map[string]terraform.ResourceProvider{
@@ -19,6 +29,20 @@ func init() {
 	}
 }
 
+func TestMain(m *testing.M) {
+	if os.Getenv("RUNSCOPE_ACCESS_TOKEN") == "" {
+		testAccServer = runscopetest.NewServer()
+		os.Setenv("RUNSCOPE_API_URL", testAccServer.URL)
+		os.Setenv("RUNSCOPE_ACCESS_TOKEN", runscopetest.AccessToken)
+		os.Setenv("RUNSCOPE_TEAM_ID", runscopetest.TeamID)
+		os.Setenv("RUNSCOPE_OTHER_TEAM_ID", runscopetest.OtherTeamID)
+		os.Setenv("RUNSCOPE_INTEGRATION_DESC", runscopetest.IntegrationDescription)
+		os.Setenv("RUNSCOPE_PERSON_EMAIL", runscopetest.PersonEmail)
+	}
+
+	resource.TestMain(m)
+}
+
 func TestProvider(t *testing.T) {
 	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
@@ -30,6 +54,19 @@ func TestProviderImpl(t *testing.T) {
 	var _ terraform.ResourceProvider = Provider()
 }
 
+func TestAccProvider_invalidAccessToken(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccProviderInvalidAccessTokenConfig,
+				ExpectError: regexp.MustCompile("Invalid runscope access_token"),
+			},
+		},
+	})
+}
+
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("RUNSCOPE_ACCESS_TOKEN"); v == "" {
 		t.Fatal("RUNSCOPE_ACCESS_TOKEN must be set for acceptance tests")
@@ -43,3 +80,74 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("RUNSCOPE_INTEGRATION_DESC must be set for acceptance tests")
 	}
 }
+
+// testAccTest runs an acceptance test, against the mock runscope api the
+// test always runs, otherwise TF_ACC must be set as usual
+func testAccTest(t *testing.T, c resource.TestCase) {
+	c.IsUnitTest = testAccServer != nil
+	resource.Test(t, c)
+}
+
+// testAccImportStateIDFunc builds a composite import id from the given
+// parent attributes followed by the resource id i.e. bucket_id/test_id/id
+func testAccImportStateIDFunc(n string, attributes ...string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", n)
+		}
+
+		parts := []string{}
+		for _, attribute := range attributes {
+			parts = append(parts, rs.Primary.Attributes[attribute])
+		}
+
+		return strings.Join(append(parts, rs.Primary.ID), "/"), nil
+	}
+}
+
+const testAccProviderInvalidAccessTokenConfig = `
+provider "runscope" {
+	access_token = "invalid"
+}
+
+data "runscope_account" "account" {}
+`
+
+func TestProvider_resourceTimeouts(t *testing.T) {
+	crud := []string{schema.TimeoutCreate, schema.TimeoutRead, schema.TimeoutUpdate, schema.TimeoutDelete}
+	expected := map[string][]string{
+		"runscope_bucket":      {schema.TimeoutCreate, schema.TimeoutRead, schema.TimeoutDelete},
+		"runscope_environment": crud,
+		"runscope_schedule":    crud,
+		"runscope_step":        crud,
+		"runscope_test":        crud,
+		// test runs are only removed from state, and updates re-read the results
+		"runscope_test_run": {schema.TimeoutCreate, schema.TimeoutRead},
+	}
+
+	resources := Provider().(*schema.Provider).ResourcesMap
+	if len(resources) != len(expected) {
+		t.Fatalf("Expected timeouts for %d resources, provider has %d", len(expected), len(resources))
+	}
+
+	for name, operations := range expected {
+		r, ok := resources[name]
+		if !ok || r.Timeouts == nil {
+			t.Fatalf("%s: expected timeouts to be defined", name)
+		}
+
+		timeouts := map[string]*time.Duration{
+			schema.TimeoutCreate: r.Timeouts.Create,
+			schema.TimeoutRead:   r.Timeouts.Read,
+			schema.TimeoutUpdate: r.Timeouts.Update,
+			schema.TimeoutDelete: r.Timeouts.Delete,
+		}
+
+		for _, operation := range operations {
+			if timeouts[operation] == nil {
+				t.Fatalf("%s: expected a %s timeout", name, operation)
+			}
+		}
+	}
+}
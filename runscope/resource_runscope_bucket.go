@@ -3,7 +3,7 @@ package runscope
 import (
 	"fmt"
 	"log"
-	"strings"
+	"time"
 
 	"github.com/ewilde/go-runscope"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -14,6 +14,15 @@ func resourceRunscopeBucket() *schema.Resource {
 		Create: resourceBucketCreate,
 		Read:   resourceBucketRead,
 		Delete: resourceBucketDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -23,7 +32,8 @@ func resourceRunscopeBucket() *schema.Resource {
 			},
 			"team_uuid": &schema.Schema{
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 				ForceNew: true,
 			},
 		},
@@ -31,18 +41,25 @@ func resourceRunscopeBucket() *schema.Resource {
 }
 
 func resourceBucketCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutCreate))
+	defer cancel()
 
 	name := d.Get("name").(string)
 	log.Printf("[INFO] Creating bucket for name: %s", name)
 
-	bucket, err := createBucketFromResourceData(d)
+	teamID, err := client.teamID(d)
+	if err != nil {
+		return err
+	}
+
+	bucket, err := createBucketFromResourceData(d, teamID)
 	if err != nil {
 		return err
 	}
-	log.Printf("[DEBUG] bucket create: %#v", bucket)
+	log.Printf("[DEBUG] bucket create: %s", runscope.Redact(bucket))
 
-	createdBucket, err := client.CreateBucket(bucket)
+	createdBucket, err := client.CreateBucket(ctx, bucket)
 	if err != nil {
 		return fmt.Errorf("Failed to create bucket: %s", err)
 	}
@@ -54,15 +71,17 @@ func resourceBucketCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceBucketRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutRead))
+	defer cancel()
 
 	key := d.Id()
 	name := d.Get("name").(string)
 	log.Printf("[INFO] Reading bucket for id: %s name: %s", key, name)
 
-	bucket, err := client.ReadBucket(key)
+	bucket, err := client.ReadBucket(ctx, key)
 	if err != nil {
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "403") {
+		if runscope.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
@@ -76,28 +95,29 @@ func resourceBucketRead(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceBucketDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutDelete))
+	defer cancel()
 
 	key := d.Id()
 	name := d.Get("name").(string)
 	log.Printf("[INFO] Deleting bucket with key: %s name: %s", key, name)
 
-	if err := client.DeleteBucket(key); err != nil {
+	if err := client.DeleteBucket(ctx, key); err != nil {
 		return fmt.Errorf("Error deleting bucket: %s", err)
 	}
 
 	return nil
 }
 
-func createBucketFromResourceData(d *schema.ResourceData) (*runscope.Bucket, error) {
+func createBucketFromResourceData(d *schema.ResourceData, teamID string) (*runscope.Bucket, error) {
 
 	bucket := runscope.Bucket{}
 	if attr, ok := d.GetOk("name"); ok {
 		bucket.Name = attr.(string)
 	}
-	if attr, ok := d.GetOk("team_uuid"); ok {
-		bucket.Team = &runscope.Team{ID: attr.(string)}
-	}
+
+	bucket.Team = &runscope.Team{ID: teamID}
 
 	return &bucket, nil
 }
@@ -1,8 +1,12 @@
 package runscope
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"regexp"
 	"testing"
 
 	"log"
@@ -17,7 +21,7 @@ func TestAccBucket_basic(t *testing.T) {
 	var bucketResponse runscope.Bucket
 	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
 
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckBucketDestroy,
@@ -30,10 +34,108 @@ func TestAccBucket_basic(t *testing.T) {
 						"runscope_bucket.bucket", "name", "runscope-bucket"),
 				),
 			},
+			{
+				ResourceName:      "runscope_bucket.bucket",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccBucket_providerTeam(t *testing.T) {
+	var bucketKey string
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBucketDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testRunscopeBucketConfigA, teamID),
+				Check:  testAccCheckBucketKey("runscope_bucket.bucket", &bucketKey),
+			},
+			{
+				Config: fmt.Sprintf(testRunscopeBucketConfigProviderTeam, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBucketKey("runscope_bucket.bucket", &bucketKey),
+					resource.TestCheckResourceAttr("runscope_bucket.bucket", "team_uuid", teamID),
+				),
+			},
 		},
 	})
 }
 
+func TestAccBucket_timeouts(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBucketDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testRunscopeBucketConfigTimeouts, teamID, "update"),
+				ExpectError: regexp.MustCompile("Timeout Key \\(update\\) is not supported"),
+			},
+			{
+				Config: fmt.Sprintf(testRunscopeBucketConfigTimeouts, teamID, "create"),
+				Check: resource.TestCheckResourceAttr(
+					"runscope_bucket.bucket", "name", "runscope-bucket"),
+			},
+		},
+	})
+}
+
+func TestBucketRead_errors(t *testing.T) {
+	cases := []struct {
+		status    int
+		removed   bool
+		expectErr bool
+	}{
+		{http.StatusNotFound, true, false},
+		{http.StatusForbidden, false, true},
+		{http.StatusUnauthorized, false, true},
+		{http.StatusInternalServerError, false, true},
+	}
+
+	for _, c := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(c.status)
+			fmt.Fprintf(w, `{"meta": {"status": "error"}, "data": [], "error": {"status": %d, "error": "failed"}}`, c.status)
+		}))
+
+		// refreshed through the resource so the operation timeouts are populated as they are by terraform
+		state := &terraform.InstanceState{
+			ID:         "key",
+			Attributes: map[string]string{"name": "bucket", "team_uuid": "team"},
+		}
+
+		client := runscope.NewClient(server.URL, "token")
+		client.MaxRetries = 0
+
+		refreshed, err := resourceRunscopeBucket().Refresh(state, &runscopeClient{Client: client})
+		server.Close()
+
+		if c.expectErr && err == nil {
+			t.Fatalf("Expected an error reading bucket with status %d", c.status)
+		}
+
+		if !c.expectErr && err != nil {
+			t.Fatalf("Unexpected error reading bucket with status %d: %s", c.status, err)
+		}
+
+		if c.removed != (refreshed == nil) {
+			t.Fatalf("Expected bucket removed from state to be %t with status %d", c.removed, c.status)
+		}
+
+		if c.expectErr && !strings.Contains(err.Error(), "reason: \"failed\"") {
+			t.Fatalf("Expected error to include the api error message, actual %s", err)
+		}
+	}
+}
+
 func init() {
 	resource.AddTestSweepers("runscope_bucket", &resource.Sweeper{
 		Name: "runscope_bucket",
@@ -59,21 +161,21 @@ func init() {
 				return false
 			}
 
-			client.DeleteBuckets(shouldDeleteBucket)
+			client.DeleteBuckets(context.Background(), shouldDeleteBucket)
 			return nil
 		},
 	})
 }
 
 func testAccCheckBucketDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*runscope.Client)
+	client := testAccProvider.Meta().(*runscopeClient)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "runscope_bucket" {
 			continue
 		}
 
-		_, err := client.ReadBucket(rs.Primary.ID)
+		_, err := client.ReadBucket(context.Background(), rs.Primary.ID)
 
 		if err == nil {
 			return fmt.Errorf("Record %s still exists", rs.Primary.ID)
@@ -95,9 +197,9 @@ func testAccCheckBucketExists(n string, bucket *runscope.Bucket) resource.TestCh
 			return fmt.Errorf("No Record ID is set")
 		}
 
-		client := testAccProvider.Meta().(*runscope.Client)
+		client := testAccProvider.Meta().(*runscopeClient)
 
-		foundRecord, err := client.ReadBucket(rs.Primary.ID)
+		foundRecord, err := client.ReadBucket(context.Background(), rs.Primary.ID)
 
 		if err != nil {
 			return err
@@ -113,6 +215,44 @@ func testAccCheckBucketExists(n string, bucket *runscope.Bucket) resource.TestCh
 	}
 }
 
+// testAccCheckBucketKey records the key of the bucket the first time it is called,
+// and checks the bucket has not been replaced on the following calls
+func testAccCheckBucketKey(n string, key *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if *key == "" {
+			*key = rs.Primary.ID
+		} else if *key != rs.Primary.ID {
+			return fmt.Errorf("Expected bucket %s to be kept, actual %s", *key, rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+const testRunscopeBucketConfigProviderTeam = `
+provider "runscope" {
+  team_uuid = "%s"
+}
+
+resource "runscope_bucket" "bucket" {
+  name = "runscope-bucket"
+}`
+
+const testRunscopeBucketConfigTimeouts = `
+resource "runscope_bucket" "bucket" {
+  name = "runscope-bucket"
+  team_uuid = "%s"
+
+  timeouts {
+    %s = "2m"
+  }
+}`
+
 const testRunscopeBucketConfigA = `
 resource "runscope_bucket" "bucket" {
   name = "runscope-bucket"
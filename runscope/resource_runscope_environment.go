@@ -1,20 +1,43 @@
 package runscope
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/ewilde/go-runscope"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
+// How secret_variables read back from runscope are compared with the configuration
+const (
+	// secretVariablesDiffValue compares the values, detecting changes made outside of terraform
+	secretVariablesDiffValue = "value"
+
+	// secretVariablesDiffKeys only compares which secret variables exist, keeping the
+	// configured values, for when runscope masks secret values in its responses
+	secretVariablesDiffKeys = "keys"
+)
+
 func resourceRunscopeEnvironment() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceEnvironmentCreate,
 		Read:   resourceEnvironmentRead,
 		Update: resourceEnvironmentUpdate,
 		Delete: resourceEnvironmentDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceEnvironmentImport,
+		},
+		CustomizeDiff: resourceEnvironmentCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"bucket_id": &schema.Schema{
@@ -48,6 +71,18 @@ func resourceRunscopeEnvironment() *schema.Resource {
 				Optional: true,
 				ForceNew: false,
 			},
+			"secret_variables": &schema.Schema{
+				Type:      schema.TypeMap,
+				Elem:      &schema.Schema{Type: schema.TypeString},
+				Optional:  true,
+				Sensitive: true,
+			},
+			"secret_variables_diff_mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      secretVariablesDiffValue,
+				ValidateFunc: validateStringInSlice([]string{secretVariablesDiffValue, secretVariablesDiffKeys}),
+			},
 			"integrations": &schema.Schema{
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -56,7 +91,13 @@ func resourceRunscopeEnvironment() *schema.Resource {
 			"regions": &schema.Schema{
 				Type:     schema.TypeSet,
 				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+					// see https://www.runscope.com/docs/regions
+					ValidateFunc: validateStringInSlice([]string{"us1", "us2", "us3", "us4", "eu1", "eu2",
+						"ap1", "ap2", "ap3", "ap4", "sa1", "ca1"}),
+				},
 			},
 			"remote_agents": &schema.Schema{
 				Type: schema.TypeSet,
@@ -83,12 +124,79 @@ func resourceRunscopeEnvironment() *schema.Resource {
 				Optional: true,
 				Default:  true,
 			},
+			"webhooks": &schema.Schema{
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"parent_environment_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"client_certificate": &schema.Schema{
+				Type:      schema.TypeString,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"email": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"notify_all": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+						},
+						"notify_on": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateStringInSlice([]string{"all", "failures", "threshold", "switch"}),
+						},
+						"notify_threshold": &schema.Schema{
+							Type:     schema.TypeInt,
+							Optional: true,
+							Computed: true,
+						},
+						"recipients": &schema.Schema{
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": &schema.Schema{
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
+									},
+									"name": &schema.Schema{
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
+									},
+									"email": &schema.Schema{
+										Type:     schema.TypeString,
+										Optional: true,
+										Computed: true,
+										DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+											return strings.EqualFold(old, new)
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
 func resourceEnvironmentCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutCreate))
+	defer cancel()
 
 	name := d.Get("name").(string)
 	log.Printf("[INFO] Creating environment with name: %s", name)
@@ -97,16 +205,19 @@ func resourceEnvironmentCreate(d *schema.ResourceData, meta interface{}) error {
 	if err != nil {
 		return err
 	}
-	log.Printf("[DEBUG] environment create: %#v", environment)
+	log.Printf("[DEBUG] environment create: %s", runscope.Redact(environment))
 
 	var createdEnvironment *runscope.Environment
 	bucketID := d.Get("bucket_id").(string)
+	if err := resolveEmailRecipients(ctx, client, bucketID, environment.EmailSettings); err != nil {
+		return err
+	}
 
 	if testID, ok := d.GetOk("test_id"); ok {
-		createdEnvironment, err = client.CreateTestEnvironment(environment,
+		createdEnvironment, err = client.CreateTestEnvironment(ctx, environment,
 			&runscope.Test{ID: testID.(string), Bucket: &runscope.Bucket{Key: bucketID}})
 	} else {
-		createdEnvironment, err = client.CreateSharedEnvironment(environment,
+		createdEnvironment, err = client.CreateSharedEnvironment(ctx, environment,
 			&runscope.Bucket{Key: bucketID})
 	}
 	if err != nil {
@@ -120,7 +231,9 @@ func resourceEnvironmentCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceEnvironmentRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutRead))
+	defer cancel()
 
 	environmentFromResource, err := createEnvironmentFromResourceData(d)
 	if err != nil {
@@ -131,14 +244,14 @@ func resourceEnvironmentRead(d *schema.ResourceData, meta interface{}) error {
 	bucketID := d.Get("bucket_id").(string)
 	if testID, ok := d.GetOk("test_id"); ok {
 		environment, err = client.ReadTestEnvironment(
-			environmentFromResource, &runscope.Test{ID: testID.(string), Bucket: &runscope.Bucket{Key: bucketID}})
+			ctx, environmentFromResource, &runscope.Test{ID: testID.(string), Bucket: &runscope.Bucket{Key: bucketID}})
 	} else {
 		environment, err = client.ReadSharedEnvironment(
-			environmentFromResource, &runscope.Bucket{Key: bucketID})
+			ctx, environmentFromResource, &runscope.Bucket{Key: bucketID})
 	}
 
 	if err != nil {
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "403") {
+		if runscope.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
@@ -146,15 +259,24 @@ func resourceEnvironmentRead(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Couldn't find environment: %s", err)
 	}
 
+	if environment.ParentEnvironmentID != "" {
+		parent, err := client.ReadSharedEnvironment(
+			ctx, &runscope.Environment{ID: environment.ParentEnvironmentID}, &runscope.Bucket{Key: bucketID})
+		switch {
+		case runscope.IsNotFound(err):
+			// the parent was deleted outside of terraform, so there are no inherited values to remove
+			log.Printf("[WARN] Parent environment %s of environment %s not found", environment.ParentEnvironmentID, d.Id())
+		case err != nil:
+			return fmt.Errorf("Couldn't find parent environment %s: %s", environment.ParentEnvironmentID, err)
+		default:
+			removeInheritedValues(d, environment, parent)
+		}
+	}
+
 	d.Set("bucket_id", bucketID)
 	d.Set("test_id", d.Get("test_id").(string))
-	d.Set("name", environment.Name)
-	d.Set("script", environment.Script)
-	d.Set("preserve_cookies", environment.PreserveCookies)
-	d.Set("initial_variables", environment.InitialVariables)
-	d.Set("integrations", readIntegrations(environment.Integrations))
-	d.Set("retry_on_failure", environment.RetryOnFailure)
-	d.Set("verify_ssl", environment.VerifySsl)
+	setEnvironmentResourceData(d, environment)
+
 	return nil
 }
 
@@ -169,30 +291,43 @@ func resourceEnvironmentUpdate(d *schema.ResourceData, meta interface{}) error {
 		d.HasChange("script") ||
 		d.HasChange("preserve_cookies") ||
 		d.HasChange("initial_variables") ||
+		d.HasChange("secret_variables") ||
 		d.HasChange("integrations") ||
 		d.HasChange("regions") ||
 		d.HasChange("remote_agents") ||
 		d.HasChange("retry_on_failure") ||
-		d.HasChange("verify_ssl") {
-		client := meta.(*runscope.Client)
+		d.HasChange("verify_ssl") ||
+		d.HasChange("webhooks") ||
+		d.HasChange("parent_environment_id") ||
+		d.HasChange("client_certificate") ||
+		d.HasChange("email") {
+		client := meta.(*runscopeClient)
+		ctx, cancel := client.newContext(d.Timeout(schema.TimeoutUpdate))
+		defer cancel()
 		bucketID := d.Get("bucket_id").(string)
+		if err := resolveEmailRecipients(ctx, client, bucketID, environment.EmailSettings); err != nil {
+			return err
+		}
+
 		if testID, ok := d.GetOk("test_id"); ok {
 			_, err = client.UpdateTestEnvironment(
-				environment, &runscope.Test{ID: testID.(string), Bucket: &runscope.Bucket{Key: bucketID}})
+				ctx, environment, &runscope.Test{ID: testID.(string), Bucket: &runscope.Bucket{Key: bucketID}})
 		} else {
 			_, err = client.UpdateSharedEnvironment(
-				environment, &runscope.Bucket{Key: bucketID})
+				ctx, environment, &runscope.Bucket{Key: bucketID})
 		}
 		if err != nil {
 			return fmt.Errorf("Error updating environment: %s", err)
 		}
 	}
 
-	return nil
+	return resourceEnvironmentRead(d, meta)
 }
 
 func resourceEnvironmentDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutDelete))
+	defer cancel()
 
 	environmentFromResource, err := createEnvironmentFromResourceData(d)
 	if err != nil {
@@ -204,12 +339,12 @@ func resourceEnvironmentDelete(d *schema.ResourceData, meta interface{}) error {
 		log.Printf("[INFO] Deleting test environment with id: %s name: %s, from test %s",
 			environmentFromResource.ID, environmentFromResource.Name, testID.(string))
 		err = client.DeleteEnvironment(
-			environmentFromResource, &runscope.Bucket{Key: bucketID})
+			ctx, environmentFromResource, &runscope.Bucket{Key: bucketID})
 	} else {
 		log.Printf("[INFO] Deleting shared environment with id: %s name: %s",
 			environmentFromResource.ID, environmentFromResource.Name)
 		err = client.DeleteEnvironment(
-			environmentFromResource, &runscope.Bucket{Key: bucketID})
+			ctx, environmentFromResource, &runscope.Bucket{Key: bucketID})
 	}
 
 	if err != nil {
@@ -219,11 +354,43 @@ func resourceEnvironmentDelete(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+func resourceEnvironmentImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if strings.Count(d.Id(), "/") == 2 {
+		parts, err := parseImportID(d.Id(), "bucket_key/test_id/environment_id")
+		if err != nil {
+			return nil, err
+		}
+
+		d.Set("bucket_id", parts[0])
+		d.Set("test_id", parts[1])
+		d.SetId(parts[2])
+	} else {
+		parts, err := parseImportID(d.Id(), "bucket_key/environment_id")
+		if err != nil {
+			return nil, err
+		}
+
+		d.Set("bucket_id", parts[0])
+		d.SetId(parts[1])
+	}
+
+	// secret variables can not be told apart from initial variables, so they are
+	// imported as initial_variables and moved once secret_variables is configured
+	d.Set("secret_variables_diff_mode", secretVariablesDiffValue)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func createEnvironmentFromResourceData(d *schema.ResourceData) (*runscope.Environment, error) {
 
 	environment := runscope.NewEnvironment()
 	environment.ID = d.Id()
 
+	environment.InitialVariables = map[string]string{}
+	environment.Integrations = []*runscope.EnvironmentIntegration{}
+	environment.RemoteAgents = []*runscope.LocalMachine{}
+	environment.WebHooks = []string{}
+
 	if attr, ok := d.GetOk("name"); ok {
 		environment.Name = attr.(string)
 	}
@@ -242,41 +409,38 @@ func createEnvironmentFromResourceData(d *schema.ResourceData) (*runscope.Enviro
 
 	if attr, ok := d.GetOk("initial_variables"); ok {
 		variablesRaw := attr.(map[string]interface{})
-		variables := map[string]string{}
 		for k, v := range variablesRaw {
-			variables[k] = v.(string)
+			environment.InitialVariables[k] = v.(string)
 		}
+	}
+
+	// secret variables are sent to runscope as initial variables
+	if attr, ok := d.GetOk("secret_variables"); ok {
+		for k, v := range attr.(map[string]interface{}) {
+			if _, ok := environment.InitialVariables[k]; ok {
+				return nil, fmt.Errorf("Variable %s is set in both initial_variables and secret_variables, set it in only one", k)
+			}
 
-		environment.InitialVariables = variables
+			environment.InitialVariables[k] = v.(string)
+		}
 	}
 
 	if attr, ok := d.GetOk("integrations"); ok {
-		integrations := []*runscope.EnvironmentIntegration{}
 		items := attr.(*schema.Set)
 		for _, item := range items.List() {
 			integration := runscope.EnvironmentIntegration{
 				ID: item.(string),
 			}
 
-			integrations = append(integrations, &integration)
+			environment.Integrations = append(environment.Integrations, &integration)
 		}
-
-		environment.Integrations = integrations
 	}
 
 	if attr, ok := d.GetOk("regions"); ok {
-		regions := []string{}
-		items := attr.(*schema.Set)
-		for _, x := range items.List() {
-			item := x.(string)
-			regions = append(regions, item)
-		}
-
-		environment.Regions = regions
+		environment.Regions = expandStringList(attr.(*schema.Set).List())
 	}
 
 	if attr, ok := d.GetOk("remote_agents"); ok {
-		remoteAgents := []*runscope.LocalMachine{}
 		items := attr.(*schema.Set)
 		for _, x := range items.List() {
 			item := x.(map[string]interface{})
@@ -285,10 +449,8 @@ func createEnvironmentFromResourceData(d *schema.ResourceData) (*runscope.Enviro
 				UUID: item["uuid"].(string),
 			}
 
-			remoteAgents = append(remoteAgents, &remoteAgent)
+			environment.RemoteAgents = append(environment.RemoteAgents, &remoteAgent)
 		}
-
-		environment.RemoteAgents = remoteAgents
 	}
 
 	if attr, ok := d.GetOk("retry_on_failure"); ok {
@@ -299,17 +461,272 @@ func createEnvironmentFromResourceData(d *schema.ResourceData) (*runscope.Enviro
 		environment.VerifySsl = attr
 	}
 
+	if attr, ok := d.GetOk("webhooks"); ok {
+		environment.WebHooks = expandStringList(attr.(*schema.Set).List())
+	}
+
+	if attr, ok := d.GetOk("parent_environment_id"); ok {
+		environment.ParentEnvironmentID = attr.(string)
+	}
+
+	if attr, ok := d.GetOk("client_certificate"); ok {
+		environment.ClientCertificate = attr.(string)
+	}
+
+	if attr, ok := d.GetOk("email"); ok {
+		environment.EmailSettings = expandEmailSettings(attr.([]interface{}))
+	}
+
+	// Empty collections are only sent when they were removed from the configuration,
+	// so that they are cleared, otherwise they are left out so that collections
+	// never configured in terraform are left as they are
+	if len(environment.InitialVariables) == 0 && !d.HasChange("initial_variables") && !d.HasChange("secret_variables") {
+		environment.InitialVariables = nil
+	}
+
+	if len(environment.Integrations) == 0 && !d.HasChange("integrations") {
+		environment.Integrations = nil
+	}
+
+	if len(environment.RemoteAgents) == 0 && !d.HasChange("remote_agents") {
+		environment.RemoteAgents = nil
+	}
+
+	if len(environment.WebHooks) == 0 && !d.HasChange("webhooks") {
+		environment.WebHooks = nil
+	}
+
 	return environment, nil
 }
 
-func readIntegrations(integrations []*runscope.EnvironmentIntegration) []map[string]interface{} {
-	result := make([]map[string]interface{}, 0, len(integrations))
+func expandEmailSettings(configured []interface{}) *runscope.EmailSettings {
+	if len(configured) == 0 || configured[0] == nil {
+		return nil
+	}
+
+	item := configured[0].(map[string]interface{})
+	emailSettings := runscope.EmailSettings{
+		NotifyAll:       item["notify_all"].(bool),
+		NotifyOn:        item["notify_on"].(string),
+		NotifyThreshold: item["notify_threshold"].(int),
+		Recipients:      []*runscope.Contact{},
+	}
+
+	if recipients, ok := item["recipients"].([]interface{}); ok {
+		for _, x := range recipients {
+			recipient := x.(map[string]interface{})
+			emailSettings.Recipients = append(emailSettings.Recipients, &runscope.Contact{
+				ID:    recipient["id"].(string),
+				Name:  recipient["name"].(string),
+				Email: recipient["email"].(string),
+			})
+		}
+	}
+
+	return &emailSettings
+}
+
+func setEnvironmentResourceData(d *schema.ResourceData, environment *runscope.Environment) {
+	d.Set("name", environment.Name)
+	d.Set("script", environment.Script)
+	d.Set("preserve_cookies", environment.PreserveCookies)
+	initialVariables, secretVariables := splitSecretVariables(d, environment.InitialVariables)
+	d.Set("initial_variables", initialVariables)
+	d.Set("secret_variables", secretVariables)
+	d.Set("integrations", readIntegrations(environment.Integrations))
+	d.Set("regions", environment.Regions)
+	d.Set("remote_agents", readRemoteAgents(environment.RemoteAgents))
+	d.Set("retry_on_failure", environment.RetryOnFailure)
+	d.Set("verify_ssl", environment.VerifySsl)
+	d.Set("webhooks", environment.WebHooks)
+	d.Set("parent_environment_id", environment.ParentEnvironmentID)
+	d.Set("client_certificate", environment.ClientCertificate)
+	d.Set("email", readEmailSettings(environment.EmailSettings))
+}
+
+// splitSecretVariables separates the variables read from runscope into initial_variables
+// and the secret_variables set in the configuration. With the keys diff mode the
+// configured value of a secret variable is kept rather than the value runscope returned.
+func splitSecretVariables(d *schema.ResourceData, variables map[string]string) (map[string]string, map[string]string) {
+	configured := d.Get("secret_variables").(map[string]interface{})
+	keepValues := d.Get("secret_variables_diff_mode").(string) == secretVariablesDiffKeys
+
+	initialVariables := map[string]string{}
+	secretVariables := map[string]string{}
+	for k, v := range variables {
+		value, ok := configured[k]
+		if !ok {
+			initialVariables[k] = v
+			continue
+		}
+
+		if keepValues {
+			v = value.(string)
+		}
+
+		secretVariables[k] = v
+	}
+
+	return initialVariables, secretVariables
+}
+
+// removeInheritedValues removes the values an environment inherits from its parent
+// environment, unless they are also set on the environment itself, so that they are
+// not reported as drift
+func removeInheritedValues(d *schema.ResourceData, environment *runscope.Environment, parent *runscope.Environment) {
+	if environment.Script == parent.Script && d.Get("script").(string) == "" {
+		environment.Script = ""
+	}
+
+	variables := d.Get("initial_variables").(map[string]interface{})
+	secretVariables := d.Get("secret_variables").(map[string]interface{})
+	for k, v := range environment.InitialVariables {
+		if _, ok := variables[k]; ok {
+			continue
+		}
+
+		if _, ok := secretVariables[k]; ok {
+			continue
+		}
+
+		if parentValue, ok := parent.InitialVariables[k]; ok && parentValue == v {
+			delete(environment.InitialVariables, k)
+		}
+	}
+
+	webhooks := d.Get("webhooks").(*schema.Set)
+	ownWebhooks := []string{}
+	for _, webhook := range environment.WebHooks {
+		if contains(parent.WebHooks, webhook) && !webhooks.Contains(webhook) {
+			continue
+		}
+
+		ownWebhooks = append(ownWebhooks, webhook)
+	}
+	environment.WebHooks = ownWebhooks
+}
+
+// resourceEnvironmentCustomizeDiff checks the parent of a new or changed environment, once
+// the parent, test and bucket are known, which may be when applying
+func resourceEnvironmentCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("parent_environment_id") && !d.HasChange("test_id") && !d.HasChange("bucket_id") {
+		return nil
+	}
+
+	// a parent only known when applying is still set, so a shared environment setting one is reported now
+	if !d.NewValueKnown("parent_environment_id") {
+		if d.NewValueKnown("test_id") && d.Get("test_id").(string) == "" {
+			return fmt.Errorf("parent_environment_id can only be set on a test environment, set test_id or remove parent_environment_id")
+		}
+
+		return nil
+	}
+
+	if !d.NewValueKnown("bucket_id") || !d.NewValueKnown("test_id") {
+		return nil
+	}
+
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(planTimeout)
+	defer cancel()
+
+	return validateParentEnvironment(ctx, client, d.Get("bucket_id").(string), d.Get("test_id").(string),
+		d.Get("parent_environment_id").(string))
+}
+
+// validateParentEnvironment checks only test environments set a parent, and that
+// the parent is a shared environment in the same bucket
+func validateParentEnvironment(ctx context.Context, client *runscopeClient, bucketID string, testID string, parentID string) error {
+	if parentID == "" {
+		return nil
+	}
+
+	if testID == "" {
+		return fmt.Errorf("parent_environment_id can only be set on a test environment, set test_id or remove parent_environment_id")
+	}
+
+	parent, err := client.ReadSharedEnvironment(ctx, &runscope.Environment{ID: parentID},
+		&runscope.Bucket{Key: bucketID})
+	if err != nil {
+		if runscope.IsNotFound(err) {
+			return fmt.Errorf("Parent environment %s is not a shared environment in bucket %s", parentID, bucketID)
+		}
+
+		return fmt.Errorf("Error reading parent environment %s: %s", parentID, err)
+	}
+
+	if parent.TestID != "" {
+		return fmt.Errorf("Parent environment %s belongs to test %s, only shared environments can be a parent",
+			parentID, parent.TestID)
+	}
+
+	return nil
+}
+
+// resolveEmailRecipients looks up the id of recipients configured by email address
+// from the people in the team the bucket belongs to
+func resolveEmailRecipients(ctx context.Context, client *runscopeClient, bucketID string, emailSettings *runscope.EmailSettings) error {
+	if emailSettings == nil {
+		return nil
+	}
+
+	var people []*runscope.People
+	for _, recipient := range emailSettings.Recipients {
+		if recipient.ID != "" {
+			continue
+		}
+
+		if recipient.Email == "" {
+			return fmt.Errorf("Email recipients must set either an id or an email")
+		}
+
+		if people == nil {
+			bucket, err := client.ReadBucket(ctx, bucketID)
+			if err != nil {
+				return fmt.Errorf("Error reading bucket %s to resolve email recipients: %s", bucketID, err)
+			}
+
+			if bucket.Team == nil {
+				return fmt.Errorf("Unable to resolve email recipients, bucket %s has no team", bucketID)
+			}
+
+			if people, err = client.ListPeople(ctx, bucket.Team.ID); err != nil {
+				return fmt.Errorf("Error listing people to resolve email recipients: %s", err)
+			}
+		}
+
+		for _, person := range people {
+			if strings.EqualFold(person.Email, recipient.Email) {
+				recipient.ID = person.ID
+				recipient.Name = person.Name
+				break
+			}
+		}
+
+		if recipient.ID == "" {
+			return fmt.Errorf("Unable to locate a person in the team with the email: %s", recipient.Email)
+		}
+	}
+
+	return nil
+}
+
+func readIntegrations(integrations []*runscope.EnvironmentIntegration) []string {
+	result := make([]string, 0, len(integrations))
 	for _, integration := range integrations {
+		result = append(result, integration.ID)
+	}
+
+	return result
+}
+
+func readRemoteAgents(remoteAgents []*runscope.LocalMachine) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(remoteAgents))
+	for _, remoteAgent := range remoteAgents {
 
 		item := map[string]interface{}{
-			"id":               integration.ID,
-			"integration_type": integration.IntegrationType,
-			"description":      integration.Description,
+			"name": remoteAgent.Name,
+			"uuid": remoteAgent.UUID,
 		}
 
 		result = append(result, item)
@@ -317,3 +734,26 @@ func readIntegrations(integrations []*runscope.EnvironmentIntegration) []map[str
 
 	return result
 }
+
+func readEmailSettings(emailSettings *runscope.EmailSettings) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, 1)
+	if emailSettings == nil {
+		return result
+	}
+
+	recipients := make([]interface{}, 0, len(emailSettings.Recipients))
+	for _, recipient := range emailSettings.Recipients {
+		recipients = append(recipients, map[string]interface{}{
+			"id":    recipient.ID,
+			"name":  recipient.Name,
+			"email": recipient.Email,
+		})
+	}
+
+	return append(result, map[string]interface{}{
+		"notify_all":       emailSettings.NotifyAll,
+		"notify_on":        emailSettings.NotifyOn,
+		"notify_threshold": emailSettings.NotifyThreshold,
+		"recipients":       recipients,
+	})
+}
@@ -1,19 +1,25 @@
 package runscope
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/ewilde/go-runscope"
+	"github.com/ewilde/terraform-provider-runscope/runscopetest"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
 
 func TestAccEnvironment_basic(t *testing.T) {
 	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckEnvironmentDestroy,
@@ -27,12 +33,42 @@ func TestAccEnvironment_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(
 						"runscope_environment.environmentA", "verify_ssl", "true")),
 			},
+			{
+				ResourceName:      "runscope_environment.environmentA",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("runscope_environment.environmentA", "bucket_id"),
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
+func TestAccEnvironment_remove_collections(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckEnvironmentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testRunscopeEnvrionmentConfigA, teamID, teamID),
+				Check:  testAccCheckEnvironmentExists("runscope_environment.environmentA"),
+			},
+			{
+				Config: fmt.Sprintf(testRunscopeEnvrionmentConfigRemovedCollections, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckEnvironmentCollectionsCleared("runscope_environment.environmentA"),
+					resource.TestCheckResourceAttr("runscope_environment.environmentA", "integrations.#", "0"),
+					resource.TestCheckResourceAttr("runscope_environment.environmentA", "initial_variables.%", "0"),
+					resource.TestCheckResourceAttr("runscope_environment.environmentA", "remote_agents.#", "0"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccEnvironment_do_not_verify_ssl(t *testing.T) {
 	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckEnvironmentDestroy,
@@ -50,8 +86,396 @@ func TestAccEnvironment_do_not_verify_ssl(t *testing.T) {
 	})
 }
 
+func TestAccEnvironment_import_test_environment(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckEnvironmentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testRunscopeEnvrionmentConfigTestEnvironment, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"runscope_environment.environment", "name", "test-only-environment")),
+			},
+			{
+				ResourceName:      "runscope_environment.environment",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("runscope_environment.environment", "bucket_id", "test_id"),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccEnvironment_email(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	email := os.Getenv("RUNSCOPE_PERSON_EMAIL")
+	if email == "" {
+		t.Skip("RUNSCOPE_PERSON_EMAIL must be set to the email of a person in the team for this acceptance test")
+	}
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckEnvironmentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testRunscopeEnvrionmentConfigEmail, teamID, "sometimes", email),
+				ExpectError: regexp.MustCompile(`must be one of all, failures, threshold, switch, got: sometimes`),
+			},
+			{
+				Config: fmt.Sprintf(testRunscopeEnvrionmentConfigEmail, teamID, "threshold", email),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckEnvironmentRecipient("runscope_environment.environment", email),
+					resource.TestCheckResourceAttr("runscope_environment.environment", "email.#", "1"),
+					resource.TestCheckResourceAttr("runscope_environment.environment", "email.0.notify_on", "threshold"),
+					resource.TestCheckResourceAttr("runscope_environment.environment", "email.0.notify_threshold", "3"),
+					resource.TestCheckResourceAttr("runscope_environment.environment", "email.0.recipients.#", "1"),
+					resource.TestCheckResourceAttrSet("runscope_environment.environment", "email.0.recipients.0.id"),
+					resource.TestCheckResourceAttr("runscope_environment.environment", "email.0.recipients.0.email", email),
+				),
+			},
+		},
+	})
+}
+
+func TestAccEnvironment_parent(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckEnvironmentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testRunscopeEnvrionmentConfigParent, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"runscope_environment.child", "parent_environment_id",
+						"runscope_environment.parent", "id"),
+					resource.TestCheckResourceAttr("runscope_environment.child", "initial_variables.%", "1"),
+					resource.TestCheckResourceAttr("runscope_environment.child", "initial_variables.child_var", "child"),
+					resource.TestCheckResourceAttr("runscope_environment.child", "script", ""),
+					resource.TestCheckResourceAttr("runscope_environment.child", "webhooks.#", "1"),
+					resource.TestCheckResourceAttr("runscope_environment.child", "client_certificate", "-----BEGIN CERTIFICATE-----"),
+				),
+			},
+			{
+				ResourceName:            "runscope_environment.child",
+				ImportState:             true,
+				ImportStateIdFunc:       testAccImportStateIDFunc("runscope_environment.child", "bucket_id", "test_id"),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"client_certificate"},
+			},
+		},
+	})
+}
+
+func TestAccEnvironment_parent_deleted(t *testing.T) {
+	var parentID, bucketID string
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckEnvironmentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testRunscopeEnvrionmentConfigParent, teamID),
+				Check: func(s *terraform.State) error {
+					parent := s.RootModule().Resources["runscope_environment.parent"].Primary
+					parentID, bucketID = parent.ID, parent.Attributes["bucket_id"]
+					return nil
+				},
+			},
+			{
+				// deleting the parent outside of terraform recreates it on the next apply,
+				// rather than failing to refresh the child environment
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*runscopeClient)
+					if err := client.DeleteEnvironment(context.Background(), &runscope.Environment{ID: parentID},
+						&runscope.Bucket{Key: bucketID}); err != nil {
+						t.Fatalf("Failed to delete parent environment: %s", err)
+					}
+				},
+				Config: fmt.Sprintf(testRunscopeEnvrionmentConfigParent, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"runscope_environment.child", "parent_environment_id",
+						"runscope_environment.parent", "id"),
+					resource.TestCheckResourceAttr("runscope_environment.child", "initial_variables.%", "1"),
+					resource.TestCheckResourceAttr("runscope_environment.child", "script", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccEnvironment_shared_with_parent(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckEnvironmentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testRunscopeEnvrionmentConfigSharedWithParent, testRunscopeEnvrionmentSharedChild, teamID),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("parent_environment_id can only be set on a test environment"),
+			},
+			{
+				Config: fmt.Sprintf(testRunscopeEnvrionmentConfigSharedWithParent, "", teamID),
+			},
+		},
+	})
+}
+
+func TestAccEnvironment_secret_variables(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckEnvironmentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testRunscopeEnvrionmentConfigSecretVariables, "api_key", teamID),
+				ExpectError: regexp.MustCompile("Variable api_key is set in both initial_variables and secret_variables"),
+			},
+			{
+				Config: fmt.Sprintf(testRunscopeEnvrionmentConfigSecretVariables, "token", teamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckEnvironmentVariables("runscope_environment.environment",
+						map[string]string{"api_key": "public", "token": "secret"}),
+					resource.TestCheckResourceAttr("runscope_environment.environment", "initial_variables.%", "1"),
+					resource.TestCheckResourceAttr("runscope_environment.environment", "initial_variables.api_key", "public"),
+					resource.TestCheckResourceAttr("runscope_environment.environment", "secret_variables.%", "1"),
+					resource.TestCheckResourceAttr("runscope_environment.environment", "secret_variables.token", "secret"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccEnvironment_secret_variables_masked(t *testing.T) {
+	if testAccServer != nil {
+		testAccServer.MaskVariables("masked_token")
+		defer testAccServer.MaskVariables()
+	}
+
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckEnvironmentDestroy,
+		Steps: []resource.TestStep{
+			{
+				// comparing values, the masked value read back never matches the configuration
+				Config:             fmt.Sprintf(testRunscopeEnvrionmentConfigSecretVariablesMasked, "value", teamID),
+				ExpectNonEmptyPlan: testAccServer != nil,
+				Check: func(s *terraform.State) error {
+					if testAccServer == nil {
+						return nil
+					}
+
+					return resource.TestCheckResourceAttr("runscope_environment.environment",
+						"secret_variables.masked_token", runscopetest.MaskedValue)(s)
+				},
+			},
+			{
+				Config: fmt.Sprintf(testRunscopeEnvrionmentConfigSecretVariablesMasked, "keys", teamID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("runscope_environment.environment", "secret_variables.%", "1"),
+					resource.TestCheckResourceAttr("runscope_environment.environment", "secret_variables.masked_token", "secret"),
+					resource.TestCheckResourceAttr("runscope_environment.environment", "initial_variables.%", "1"),
+				),
+			},
+			{
+				Config:   fmt.Sprintf(testRunscopeEnvrionmentConfigSecretVariablesMasked, "keys", teamID),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestEnvironmentRemoveInheritedValues(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceRunscopeEnvironment().Schema, map[string]interface{}{
+		"bucket_id": "bucket",
+		"name":      "child",
+		"initial_variables": map[string]interface{}{
+			"own":      "child",
+			"override": "parent",
+		},
+		"webhooks": []interface{}{"https://example.com/parent"},
+	})
+
+	parent := &runscope.Environment{
+		Script:           "var a = 1;",
+		InitialVariables: map[string]string{"inherited": "parent", "override": "parent", "changed": "parent"},
+		WebHooks:         []string{"https://example.com/parent", "https://example.com/inherited"},
+	}
+
+	environment := &runscope.Environment{
+		Script:           "var a = 1;",
+		InitialVariables: map[string]string{"own": "child", "inherited": "parent", "override": "parent", "changed": "child"},
+		WebHooks:         []string{"https://example.com/parent", "https://example.com/inherited", "https://example.com/child"},
+	}
+
+	removeInheritedValues(d, environment, parent)
+
+	if environment.Script != "" {
+		t.Errorf("Expected inherited script to be removed, actual %s", environment.Script)
+	}
+
+	expectedVariables := map[string]string{"own": "child", "override": "parent", "changed": "child"}
+	if !reflect.DeepEqual(environment.InitialVariables, expectedVariables) {
+		t.Errorf("Expected initial variables %#v, actual %#v", expectedVariables, environment.InitialVariables)
+	}
+
+	expectedWebhooks := []string{"https://example.com/parent", "https://example.com/child"}
+	if !reflect.DeepEqual(environment.WebHooks, expectedWebhooks) {
+		t.Errorf("Expected webhooks %#v, actual %#v", expectedWebhooks, environment.WebHooks)
+	}
+}
+
+func TestEnvironmentResourceDataRoundTrip(t *testing.T) {
+	raw := map[string]interface{}{
+		"bucket_id":        "bucket",
+		"test_id":          "test",
+		"name":             "environment",
+		"script":           "log(\"environment\");",
+		"preserve_cookies": true,
+		"initial_variables": map[string]interface{}{
+			"var1": "true",
+			"var2": "value2",
+		},
+		"secret_variables": map[string]interface{}{
+			"token": "secret",
+		},
+		"integrations": []interface{}{"integration-1", "integration-2"},
+		"regions":      []interface{}{"us1", "eu1"},
+		"remote_agents": []interface{}{
+			map[string]interface{}{"name": "test agent", "uuid": "arbitrary-string"},
+		},
+		"retry_on_failure":      true,
+		"verify_ssl":            false,
+		"webhooks":              []interface{}{"https://example.com/hook"},
+		"parent_environment_id": "parent",
+		"client_certificate":    "-----BEGIN CERTIFICATE-----",
+		"email": []interface{}{
+			map[string]interface{}{
+				"notify_all":       true,
+				"notify_on":        "threshold",
+				"notify_threshold": 3,
+				"recipients": []interface{}{
+					map[string]interface{}{"id": "person-1", "name": "Person", "email": "person@example.com"},
+				},
+			},
+		},
+	}
+
+	environmentSchema := resourceRunscopeEnvironment().Schema
+	expected := schema.TestResourceDataRaw(t, environmentSchema, raw)
+	environment, err := createEnvironmentFromResourceData(expected)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := schema.TestResourceDataRaw(t, environmentSchema, map[string]interface{}{
+		"bucket_id":        "bucket",
+		"test_id":          "test",
+		"name":             "other",
+		"secret_variables": map[string]interface{}{"token": "other"},
+	})
+	setEnvironmentResourceData(actual, environment)
+
+	for key := range environmentSchema {
+		if !testResourceDataValueEqual(actual.Get(key), expected.Get(key)) {
+			t.Fatalf("Expected %s to round trip\n\nGot:\n\n%#v\n\nExpected:\n\n%#v\n",
+				key, actual.Get(key), expected.Get(key))
+		}
+	}
+}
+
+func TestEnvironmentSensitiveAttributes(t *testing.T) {
+	expected := map[string]bool{
+		"initial_variables":  false,
+		"secret_variables":   true,
+		"client_certificate": true,
+	}
+
+	environmentSchema := resourceRunscopeEnvironment().Schema
+	for name, sensitive := range expected {
+		if environmentSchema[name].Sensitive != sensitive {
+			t.Errorf("Expected %s sensitive to be %t", name, sensitive)
+		}
+	}
+}
+
+func TestEnvironmentSplitSecretVariables(t *testing.T) {
+	variables := map[string]string{"api_key": "public", "token": "********"}
+	cases := []struct {
+		diffMode        string
+		expectedInitial map[string]string
+		expectedSecret  map[string]string
+	}{
+		{secretVariablesDiffValue, map[string]string{"api_key": "public"}, map[string]string{"token": "********"}},
+		{secretVariablesDiffKeys, map[string]string{"api_key": "public"}, map[string]string{"token": "secret"}},
+	}
+
+	for _, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourceRunscopeEnvironment().Schema, map[string]interface{}{
+			"bucket_id":                  "bucket",
+			"name":                       "environment",
+			"secret_variables":           map[string]interface{}{"token": "secret"},
+			"secret_variables_diff_mode": c.diffMode,
+		})
+
+		initialVariables, secretVariables := splitSecretVariables(d, variables)
+		if !reflect.DeepEqual(initialVariables, c.expectedInitial) {
+			t.Errorf("%s: expected initial variables %#v, actual %#v", c.diffMode, c.expectedInitial, initialVariables)
+		}
+
+		if !reflect.DeepEqual(secretVariables, c.expectedSecret) {
+			t.Errorf("%s: expected secret variables %#v, actual %#v", c.diffMode, c.expectedSecret, secretVariables)
+		}
+	}
+}
+
+func TestEnvironmentResourceDataEmptyCollections(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceRunscopeEnvironment().Schema, map[string]interface{}{
+		"bucket_id": "bucket",
+		"name":      "environment",
+	})
+
+	environment, err := createEnvironmentFromResourceData(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	body, err := json.Marshal(environment)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, field := range []string{"initial_variables", "integrations", "remote_agents", "webhooks", "emails"} {
+		if strings.Contains(string(body), fmt.Sprintf("%q", field)) {
+			t.Errorf("Expected %s not to be sent when it is not configured, got %s", field, body)
+		}
+	}
+
+	environment.Integrations = []*runscope.EnvironmentIntegration{}
+	body, err = json.Marshal(environment)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if !strings.Contains(string(body), `"integrations":[]`) {
+		t.Errorf("Expected empty integrations to be sent so they are cleared, got %s", body)
+	}
+}
+
 func testAccCheckEnvironmentDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*runscope.Client)
+	client := testAccProvider.Meta().(*runscopeClient)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "runscope_environment" {
@@ -62,10 +486,10 @@ func testAccCheckEnvironmentDestroy(s *terraform.State) error {
 		bucketID := rs.Primary.Attributes["bucket_id"]
 		testID := rs.Primary.Attributes["test_id"]
 		if testID != "" {
-			err = client.DeleteEnvironment(&runscope.Environment{ID: rs.Primary.ID},
+			err = client.DeleteEnvironment(context.Background(), &runscope.Environment{ID: rs.Primary.ID},
 				&runscope.Bucket{Key: bucketID})
 		} else {
-			err = client.DeleteEnvironment(&runscope.Environment{ID: rs.Primary.ID},
+			err = client.DeleteEnvironment(context.Background(), &runscope.Environment{ID: rs.Primary.ID},
 				&runscope.Bucket{Key: bucketID})
 		}
 
@@ -77,6 +501,81 @@ func testAccCheckEnvironmentDestroy(s *terraform.State) error {
 	return nil
 }
 
+func testAccCheckEnvironmentRecipient(n string, email string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*runscopeClient)
+		foundRecord, err := client.ReadSharedEnvironment(context.Background(), &runscope.Environment{ID: rs.Primary.ID},
+			&runscope.Bucket{Key: rs.Primary.Attributes["bucket_id"]})
+		if err != nil {
+			return err
+		}
+
+		if foundRecord.EmailSettings == nil || len(foundRecord.EmailSettings.Recipients) != 1 {
+			return fmt.Errorf("Expected 1 email recipient, actual %#v", foundRecord.EmailSettings)
+		}
+
+		recipient := foundRecord.EmailSettings.Recipients[0]
+		if recipient.ID == "" {
+			return fmt.Errorf("Expected recipient %s to be resolved to a person id", email)
+		}
+
+		if recipient.ID != rs.Primary.Attributes["email.0.recipients.0.id"] {
+			return fmt.Errorf("Expected recipient id %s, actual %s", rs.Primary.Attributes["email.0.recipients.0.id"], recipient.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckEnvironmentVariables(n string, expected map[string]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*runscopeClient)
+		environment, err := client.ReadSharedEnvironment(context.Background(), &runscope.Environment{ID: rs.Primary.ID},
+			&runscope.Bucket{Key: rs.Primary.Attributes["bucket_id"]})
+		if err != nil {
+			return err
+		}
+
+		if !reflect.DeepEqual(environment.InitialVariables, expected) {
+			return fmt.Errorf("Expected initial variables %#v, actual %#v", expected, environment.InitialVariables)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckEnvironmentCollectionsCleared(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*runscopeClient)
+		environment, err := client.ReadSharedEnvironment(context.Background(), &runscope.Environment{ID: rs.Primary.ID},
+			&runscope.Bucket{Key: rs.Primary.Attributes["bucket_id"]})
+		if err != nil {
+			return err
+		}
+
+		if len(environment.InitialVariables) != 0 || len(environment.Integrations) != 0 || len(environment.RemoteAgents) != 0 {
+			return fmt.Errorf("Expected collections removed from the configuration to be cleared, actual %#v", environment)
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckEnvironmentExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -89,7 +588,7 @@ func testAccCheckEnvironmentExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No Record ID is set")
 		}
 
-		client := testAccProvider.Meta().(*runscope.Client)
+		client := testAccProvider.Meta().(*runscopeClient)
 
 		var foundRecord *runscope.Environment
 		var err error
@@ -99,12 +598,12 @@ func testAccCheckEnvironmentExists(n string) resource.TestCheckFunc {
 		bucketID := rs.Primary.Attributes["bucket_id"]
 		testID := rs.Primary.Attributes["test_id"]
 		if testID != "" {
-			foundRecord, err = client.ReadTestEnvironment(environment,
+			foundRecord, err = client.ReadTestEnvironment(context.Background(), environment,
 				&runscope.Test{
 					ID:     testID,
 					Bucket: &runscope.Bucket{Key: bucketID}})
 		} else {
-			foundRecord, err = client.ReadSharedEnvironment(environment,
+			foundRecord, err = client.ReadSharedEnvironment(context.Background(), environment,
 				&runscope.Bucket{Key: bucketID})
 		}
 
@@ -226,3 +725,160 @@ data "runscope_integration" "slack" {
   type = "slack"
 }
 `
+
+const testRunscopeEnvrionmentConfigTestEnvironment = `
+resource "runscope_environment" "environment" {
+  bucket_id = "${runscope_bucket.bucket.id}"
+  test_id   = "${runscope_test.test.id}"
+  name      = "test-only-environment"
+
+  initial_variables {
+    var1 = "true"
+  }
+}
+
+resource "runscope_test" "test" {
+  bucket_id = "${runscope_bucket.bucket.id}"
+  name = "runscope test"
+  description = "This is a test test..."
+}
+
+resource "runscope_bucket" "bucket" {
+  name = "terraform-provider-test"
+  team_uuid = "%s"
+}
+`
+
+const testRunscopeEnvrionmentConfigEmail = `
+resource "runscope_environment" "environment" {
+  bucket_id    = "${runscope_bucket.bucket.id}"
+  name         = "test-environment"
+
+  email {
+    notify_all       = true
+    notify_on        = "%[2]s"
+    notify_threshold = 3
+    recipients = [
+      {
+        email = "%[3]s"
+      }
+    ]
+  }
+}
+
+resource "runscope_bucket" "bucket" {
+  name = "terraform-provider-test"
+  team_uuid = "%[1]s"
+}
+`
+
+const testRunscopeEnvrionmentConfigParent = `
+resource "runscope_environment" "child" {
+  bucket_id             = "${runscope_bucket.bucket.id}"
+  test_id               = "${runscope_test.test.id}"
+  name                  = "child-environment"
+  parent_environment_id = "${runscope_environment.parent.id}"
+  client_certificate    = "-----BEGIN CERTIFICATE-----"
+  webhooks              = ["https://example.com/child"]
+
+  initial_variables {
+    child_var = "child"
+  }
+}
+
+resource "runscope_environment" "parent" {
+  bucket_id = "${runscope_bucket.bucket.id}"
+  name      = "parent-environment"
+  script    = "var parent = true;"
+  webhooks  = ["https://example.com/parent"]
+
+  initial_variables {
+    parent_var = "parent"
+  }
+}
+
+resource "runscope_test" "test" {
+  bucket_id = "${runscope_bucket.bucket.id}"
+  name = "runscope test"
+  description = "This is a test test..."
+}
+
+resource "runscope_bucket" "bucket" {
+  name = "terraform-provider-test"
+  team_uuid = "%s"
+}
+`
+
+const testRunscopeEnvrionmentSharedChild = `
+resource "runscope_environment" "child" {
+  bucket_id             = "${runscope_bucket.bucket.id}"
+  name                  = "child-environment"
+  parent_environment_id = "${runscope_environment.parent.id}"
+}
+`
+
+const testRunscopeEnvrionmentConfigSharedWithParent = `%s
+resource "runscope_environment" "parent" {
+  bucket_id = "${runscope_bucket.bucket.id}"
+  name      = "parent-environment"
+}
+
+resource "runscope_bucket" "bucket" {
+  name = "terraform-provider-test"
+  team_uuid = "%s"
+}
+`
+
+const testRunscopeEnvrionmentConfigSecretVariables = `
+resource "runscope_environment" "environment" {
+  bucket_id = "${runscope_bucket.bucket.id}"
+  name      = "secret-environment"
+
+  initial_variables {
+    api_key = "public"
+  }
+
+  secret_variables {
+    %s = "secret"
+  }
+}
+
+resource "runscope_bucket" "bucket" {
+  name      = "terraform-provider-test"
+  team_uuid = "%s"
+}
+`
+
+const testRunscopeEnvrionmentConfigSecretVariablesMasked = `
+resource "runscope_environment" "environment" {
+  bucket_id                  = "${runscope_bucket.bucket.id}"
+  name                       = "masked-environment"
+  secret_variables_diff_mode = "%s"
+
+  initial_variables {
+    api_key = "public"
+  }
+
+  secret_variables {
+    masked_token = "secret"
+  }
+}
+
+resource "runscope_bucket" "bucket" {
+  name      = "terraform-provider-test"
+  team_uuid = "%s"
+}
+`
+
+const testRunscopeEnvrionmentConfigRemovedCollections = `
+resource "runscope_environment" "environmentA" {
+  bucket_id    = "${runscope_bucket.bucket.id}"
+  name         = "test-environment"
+  regions      = ["us1", "eu1"]
+}
+
+resource "runscope_bucket" "bucket" {
+  name = "terraform-provider-test"
+  team_uuid = "%s"
+}
+`
@@ -1,9 +1,10 @@
 package runscope
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"strings"
+	"time"
 
 	"github.com/ewilde/go-runscope"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -13,7 +14,19 @@ func resourceRunscopeSchedule() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceScheduleCreate,
 		Read:   resourceScheduleRead,
+		Update: resourceScheduleUpdate,
 		Delete: resourceScheduleDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceScheduleImport,
+		},
+		CustomizeDiff: resourceScheduleCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"bucket_id": &schema.Schema{
@@ -29,34 +42,37 @@ func resourceRunscopeSchedule() *schema.Resource {
 			"environment_id": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
+				ForceNew: false,
 			},
 			"interval": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     false,
+				ValidateFunc: validateStringInSlice([]string{"1m", "5m", "15m", "30m", "1h", "6h", "1d"}),
 			},
 			"note": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: false,
 				Optional: true,
-				ForceNew: true,
+				ForceNew: false,
 			},
 		},
 	}
 }
 
 func resourceScheduleCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutCreate))
+	defer cancel()
 
 	schedule, bucketID, testID, err := createScheduleFromResourceData(d)
 	if err != nil {
 		return err
 	}
 
-	log.Printf("[DEBUG] schedule create: %#v", schedule)
+	log.Printf("[DEBUG] schedule create: %s", runscope.Redact(schedule))
 
-	createdSchedule, err := client.CreateSchedule(schedule, bucketID, testID)
+	createdSchedule, err := client.CreateSchedule(ctx, schedule, bucketID, testID)
 	if err != nil {
 		return fmt.Errorf("Failed to create schedule: %s", err)
 	}
@@ -68,16 +84,18 @@ func resourceScheduleCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceScheduleRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutRead))
+	defer cancel()
 
 	scheduleFromResource, bucketID, testID, err := createScheduleFromResourceData(d)
 	if err != nil {
 		return fmt.Errorf("Failed to read schedule from resource data: %s", err)
 	}
 
-	schedule, err := client.ReadSchedule(scheduleFromResource, bucketID, testID)
+	schedule, err := client.ReadSchedule(ctx, scheduleFromResource, bucketID, testID)
 	if err != nil {
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "403") {
+		if runscope.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
@@ -93,15 +111,40 @@ func resourceScheduleRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+func resourceScheduleUpdate(d *schema.ResourceData, meta interface{}) error {
+	d.Partial(false)
+	scheduleFromResource, bucketID, testID, err := createScheduleFromResourceData(d)
+	if err != nil {
+		return fmt.Errorf("Error updating schedule: %s", err)
+	}
+
+	if d.HasChange("environment_id") ||
+		d.HasChange("interval") ||
+		d.HasChange("note") {
+		client := meta.(*runscopeClient)
+		ctx, cancel := client.newContext(d.Timeout(schema.TimeoutUpdate))
+		defer cancel()
+		_, err = client.UpdateSchedule(ctx, scheduleFromResource, bucketID, testID)
+
+		if err != nil {
+			return fmt.Errorf("Error updating schedule: %s", err)
+		}
+	}
+
+	return resourceScheduleRead(d, meta)
+}
+
 func resourceScheduleDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutDelete))
+	defer cancel()
 
 	scheduleFromResource, bucketID, testID, err := createScheduleFromResourceData(d)
 	if err != nil {
 		return fmt.Errorf("Failed to read schedule from resource data: %s", err)
 	}
 
-	err = client.DeleteSchedule(scheduleFromResource, bucketID, testID)
+	err = client.DeleteSchedule(ctx, scheduleFromResource, bucketID, testID)
 	if err != nil {
 		return fmt.Errorf("Error deleting schedule: %s", err)
 	}
@@ -109,6 +152,19 @@ func resourceScheduleDelete(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+func resourceScheduleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts, err := parseImportID(d.Id(), "bucket_key/test_id/schedule_id")
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("bucket_id", parts[0])
+	d.Set("test_id", parts[1])
+	d.SetId(parts[2])
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func createScheduleFromResourceData(d *schema.ResourceData) (*runscope.Schedule, string, string, error) {
 
 	schedule := runscope.NewSchedule()
@@ -128,3 +184,54 @@ func createScheduleFromResourceData(d *schema.ResourceData) (*runscope.Schedule,
 	schedule.EnvironmentID = environmentID
 	return schedule, bucketID, testID, nil
 }
+
+// resourceScheduleCustomizeDiff checks the environment of a new or changed schedule, once
+// the environment and test are known, which may be when applying
+func resourceScheduleCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChange("environment_id") && !d.HasChange("test_id") && !d.HasChange("bucket_id") {
+		return nil
+	}
+
+	if !d.NewValueKnown("bucket_id") || !d.NewValueKnown("test_id") || !d.NewValueKnown("environment_id") {
+		return nil
+	}
+
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(planTimeout)
+	defer cancel()
+
+	return validateScheduleEnvironment(ctx, client, d.Get("bucket_id").(string), d.Get("test_id").(string),
+		d.Get("environment_id").(string))
+}
+
+// validateScheduleEnvironment checks the environment of a schedule is either an environment
+// of the scheduled test or a shared environment in the same bucket
+func validateScheduleEnvironment(ctx context.Context, client *runscopeClient, bucketID string, testID string, environmentID string) error {
+	_, err := client.ReadTestEnvironment(ctx, &runscope.Environment{ID: environmentID},
+		&runscope.Test{ID: testID, Bucket: &runscope.Bucket{Key: bucketID}})
+	if err == nil {
+		return nil
+	}
+
+	if !runscope.IsNotFound(err) {
+		return fmt.Errorf("Error reading schedule environment %s: %s", environmentID, err)
+	}
+
+	environment, err := client.ReadSharedEnvironment(ctx, &runscope.Environment{ID: environmentID},
+		&runscope.Bucket{Key: bucketID})
+	if err != nil {
+		if runscope.IsNotFound(err) {
+			return fmt.Errorf("Environment %s is neither an environment of test %s nor a shared environment in bucket %s",
+				environmentID, testID, bucketID)
+		}
+
+		return fmt.Errorf("Error reading schedule environment %s: %s", environmentID, err)
+	}
+
+	if environment.TestID != "" && environment.TestID != testID {
+		return fmt.Errorf("Environment %s belongs to test %s, it can not be used to schedule test %s",
+			environmentID, environment.TestID, testID)
+	}
+
+	return nil
+}
@@ -1,8 +1,10 @@
 package runscope
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"regexp"
 	"testing"
 
 	"github.com/ewilde/go-runscope"
@@ -12,7 +14,7 @@ import (
 
 func TestAccSchedule_basic(t *testing.T) {
 	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckScheduleDestroy,
@@ -26,12 +28,69 @@ func TestAccSchedule_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(
 						"runscope_schedule.daily", "interval", "1d")),
 			},
+			{
+				ResourceName:      "runscope_schedule.daily",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("runscope_schedule.daily", "bucket_id", "test_id"),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccSchedule_update(t *testing.T) {
+	var scheduleID string
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScheduleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testRunscopeScheduleConfigA, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScheduleExists("runscope_schedule.daily"),
+					testAccCheckScheduleID("runscope_schedule.daily", &scheduleID, false)),
+			},
+			{
+				Config: fmt.Sprintf(testRunscopeScheduleConfigB, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScheduleExists("runscope_schedule.daily"),
+					testAccCheckScheduleID("runscope_schedule.daily", &scheduleID, true),
+					resource.TestCheckResourceAttr(
+						"runscope_schedule.daily", "note", "This is an hourly schedule"),
+					resource.TestCheckResourceAttr(
+						"runscope_schedule.daily", "interval", "1h")),
+			},
+		},
+	})
+}
+
+func TestAccSchedule_other_test_environment(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScheduleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testRunscopeScheduleConfigOtherTestEnvironment, "", teamID),
+			},
+			{
+				// the test and environment exist, so the environment is checked when planning
+				Config:      fmt.Sprintf(testRunscopeScheduleConfigOtherTestEnvironment, testRunscopeScheduleOtherTestEnvironment, teamID),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("is neither an environment of test .* nor a shared environment|belongs to test"),
+			},
+			{
+				Config: fmt.Sprintf(testRunscopeScheduleConfigOtherTestEnvironment, "", teamID),
+			},
 		},
 	})
 }
 
 func testAccCheckScheduleDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*runscope.Client)
+	client := testAccProvider.Meta().(*runscopeClient)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "runscope_schedule" {
@@ -41,7 +100,7 @@ func testAccCheckScheduleDestroy(s *terraform.State) error {
 		var err error
 		bucketID := rs.Primary.Attributes["bucket_id"]
 		testID := rs.Primary.Attributes["test_id"]
-		err = client.DeleteSchedule(&runscope.Schedule{ID: rs.Primary.ID}, bucketID, testID)
+		err = client.DeleteSchedule(context.Background(), &runscope.Schedule{ID: rs.Primary.ID}, bucketID, testID)
 
 		if err == nil {
 			return fmt.Errorf("Record %s still exists", rs.Primary.ID)
@@ -63,7 +122,7 @@ func testAccCheckScheduleExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No Record ID is set")
 		}
 
-		client := testAccProvider.Meta().(*runscope.Client)
+		client := testAccProvider.Meta().(*runscopeClient)
 
 		var foundRecord *runscope.Schedule
 		var err error
@@ -73,7 +132,7 @@ func testAccCheckScheduleExists(n string) resource.TestCheckFunc {
 		bucketID := rs.Primary.Attributes["bucket_id"]
 		testID := rs.Primary.Attributes["test_id"]
 
-		foundRecord, err = client.ReadSchedule(schedule, bucketID, testID)
+		foundRecord, err = client.ReadSchedule(context.Background(), schedule, bucketID, testID)
 
 		if err != nil {
 			return err
@@ -87,6 +146,23 @@ func testAccCheckScheduleExists(n string) resource.TestCheckFunc {
 	}
 }
 
+func testAccCheckScheduleID(n string, id *string, same bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if same && rs.Primary.ID != *id {
+			return fmt.Errorf("Expected schedule %s to be updated in place, actual %s", *id, rs.Primary.ID)
+		}
+
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
 const testRunscopeScheduleConfigA = `
 resource "runscope_schedule" "daily" {
   bucket_id      = "${runscope_bucket.bucket.id}"
@@ -117,3 +193,68 @@ resource "runscope_environment" "environment" {
   }
 }
 `
+
+const testRunscopeScheduleConfigB = `
+resource "runscope_schedule" "daily" {
+  bucket_id      = "${runscope_bucket.bucket.id}"
+  test_id        = "${runscope_test.test.id}"
+  interval       = "1h"
+  note           = "This is an hourly schedule"
+  environment_id = "${runscope_environment.environment.id}"
+}
+
+resource "runscope_test" "test" {
+  bucket_id   = "${runscope_bucket.bucket.id}"
+  name        = "runscope test"
+  description = "This is a test test..."
+}
+
+resource "runscope_bucket" "bucket" {
+  name      = "terraform-provider-test"
+  team_uuid = "%s"
+}
+
+resource "runscope_environment" "environment" {
+  bucket_id = "${runscope_bucket.bucket.id}"
+  name      = "test-environment"
+
+  initial_variables {
+    var1 = "true",
+    var2 = "value2"
+  }
+}
+`
+
+const testRunscopeScheduleOtherTestEnvironment = `
+resource "runscope_schedule" "daily" {
+  bucket_id      = "${runscope_bucket.bucket.id}"
+  test_id        = "${runscope_test.test.id}"
+  interval       = "1d"
+  environment_id = "${runscope_environment.other.id}"
+}
+`
+
+const testRunscopeScheduleConfigOtherTestEnvironment = `%s
+resource "runscope_test" "test" {
+  bucket_id   = "${runscope_bucket.bucket.id}"
+  name        = "runscope test"
+  description = "This is a test test..."
+}
+
+resource "runscope_test" "other" {
+  bucket_id   = "${runscope_bucket.bucket.id}"
+  name        = "other runscope test"
+  description = "This is another test test..."
+}
+
+resource "runscope_bucket" "bucket" {
+  name      = "terraform-provider-test"
+  team_uuid = "%s"
+}
+
+resource "runscope_environment" "other" {
+  bucket_id = "${runscope_bucket.bucket.id}"
+  test_id   = "${runscope_test.other.id}"
+  name      = "other-test-environment"
+}
+`
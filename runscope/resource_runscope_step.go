@@ -3,153 +3,320 @@ package runscope
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/ewilde/go-runscope"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
 func resourceRunscopeStep() *schema.Resource {
+	s := stepSchema(map[string]*schema.Schema{
+		"bucket_id": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"test_id": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+	})
+
+	// conflicts are only checked at plan time for this resource, they can not
+	// reference attributes of the step blocks nested in a runscope_test
+	for _, stepType := range typedStepTypes {
+		for _, other := range typedStepTypes {
+			if other.block != stepType.block {
+				s[stepType.block].ConflictsWith = append(s[stepType.block].ConflictsWith, other.block)
+			}
+		}
+
+		s[stepType.block].ConflictsWith = append(s[stepType.block].ConflictsWith, requestStepAttributes...)
+	}
+
 	return &schema.Resource{
 		Create: resourceStepCreate,
 		Read:   resourceStepRead,
 		Update: resourceStepUpdate,
 		Delete: resourceStepDelete,
-		Schema: map[string]*schema.Schema{
-			"bucket_id": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-			},
-			"test_id": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-			},
-			"step_type": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-			},
-			"method": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-			},
-			"url": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: false,
+		Importer: &schema.ResourceImporter{
+			State: resourceStepImport,
+		},
+		CustomizeDiff: resourceStepCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: s,
+	}
+}
+
+// Values accepted by the runscope api, see https://www.runscope.com/docs/api/steps
+var (
+	stepTypes   = []string{"request", "condition", "subtest", "pause", "ghost-inspector"}
+	stepMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+	authTypes   = []string{"basic"}
+
+	// response_header is kept alongside response_headers for existing configurations
+	stepSources = []string{"response_status", "response_header", "response_headers", "response_json",
+		"response_xml", "response_text", "response_time", "response_size"}
+	stepComparisons = []string{"equal", "not_equal", "is_empty", "not_empty", "contains", "does_not_contain",
+		"is_a_number", "equal_number", "is_less_than", "is_less_than_or_equal", "is_greater_than",
+		"is_greater_than_or_equal", "has_key", "has_value", "is_null"}
+
+	// sources that read a named header or a path within the response body
+	sourcesWithProperty = []string{"response_header", "response_headers", "response_json", "response_xml"}
+
+	// comparisons that do not compare against a value
+	comparisonsWithoutValue = []string{"is_empty", "not_empty", "is_a_number", "is_null"}
+)
+
+// maxPauseDuration is the longest a pause step can wait, in seconds
+const maxPauseDuration = 180
+
+// Attributes that only apply to request steps
+var requestStepAttributes = []string{"method", "url", "headers", "auth", "body", "scripts", "before_scripts"}
+
+// Step types configured using a block of the same name, which is set when a
+// step includes the block, used to check the block matches the step type
+var typedStepTypes = []struct {
+	stepType string
+	block    string
+	set      func(step *runscope.TestStep) bool
+}{
+	{"condition", "condition", func(step *runscope.TestStep) bool { return step.Comparison != "" }},
+	{"subtest", "subtest", func(step *runscope.TestStep) bool { return step.TestUUID != "" }},
+	{"pause", "pause", func(step *runscope.TestStep) bool { return step.Duration > 0 }},
+	{"ghost-inspector", "ghost_inspector", func(step *runscope.TestStep) bool { return step.GhostTestID != "" }},
+}
+
+// stepSchema adds the attributes describing a step to the given schema, shared
+// by the runscope_step resource and the step blocks of the runscope_test resource
+func stepSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	for k, v := range map[string]*schema.Schema{
+		"step_type": &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     false,
+			ValidateFunc: validateStringInSlice(stepTypes),
+		},
+		"method": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     false,
+			ValidateFunc: validateStringInSlice(stepMethods),
+		},
+		"url": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: false,
+		},
+		"variables": &schema.Schema{
+			Type: schema.TypeSet,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"property": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"source": &schema.Schema{
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validateStringInSlice(stepSources),
+					},
+				},
 			},
-			"variables": &schema.Schema{
-				Type: schema.TypeSet,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"property": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"source": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
-						},
+			Optional: true,
+		},
+		"assertions": &schema.Schema{
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"source": &schema.Schema{
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validateStringInSlice(stepSources),
+					},
+					"property": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"comparison": &schema.Schema{
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validateStringInSlice(stepComparisons),
+					},
+					"value": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
 					},
 				},
-				Optional: true,
 			},
-			"assertions": &schema.Schema{
-				Type: schema.TypeList,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"source": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"property": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"comparison": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"value": &schema.Schema{
-							Type:     schema.TypeString,
-							Optional: true,
-						},
+			Optional: true,
+		},
+		"headers": &schema.Schema{
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"header": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"value": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
 					},
 				},
-				Optional: true,
 			},
-			"headers": &schema.Schema{
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"header": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"value": &schema.Schema{
-							Type:     schema.TypeString,
-							Required: true,
-						},
+		},
+		"auth": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"username": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"auth_type": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validateStringInSlice(authTypes),
+					},
+					"password": {
+						Type:      schema.TypeString,
+						Required:  true,
+						Sensitive: true,
 					},
 				},
 			},
-			"auth": {
-				Type:     schema.TypeSet,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"username": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"auth_type": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"password": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
+		},
+		"body": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"scripts": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"before_scripts": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"condition": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"left_value": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"comparison": &schema.Schema{
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validateStringInSlice(stepComparisons),
+					},
+					"right_value": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
 					},
 				},
 			},
-			"body": {
-				Type:     schema.TypeString,
-				Optional: true,
+		},
+		"subtest": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"test_uuid": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"bucket_key": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"environment_uuid": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"params": &schema.Schema{
+						Type:     schema.TypeMap,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+				},
 			},
-			"scripts": &schema.Schema{
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"pause": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"duration": &schema.Schema{
+						Type:         schema.TypeInt,
+						Required:     true,
+						ValidateFunc: validateIntBetween(1, maxPauseDuration),
+					},
+				},
 			},
-			"before_scripts": &schema.Schema{
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"ghost_inspector": &schema.Schema{
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"test_id": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"start_url": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+				},
 			},
 		},
+	} {
+		s[k] = v
 	}
+
+	return s
 }
 
 func resourceStepCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutCreate))
+	defer cancel()
 
 	step, bucketID, testID, err := createStepFromResourceData(d)
 	if err != nil {
 		return err
 	}
 
-	log.Printf("[DEBUG] step create: %#v", step)
+	log.Printf("[DEBUG] step create: %s", runscope.Redact(step))
 
-	createdStep, err := client.CreateTestStep(step, bucketID, testID)
+	createdStep, err := client.CreateTestStep(ctx, step, bucketID, testID)
 	if err != nil {
 		return fmt.Errorf("Failed to create step: %s", err)
 	}
@@ -161,16 +328,18 @@ func resourceStepCreate(d *schema.ResourceData, meta interface{}) error {
 }
 
 func resourceStepRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutRead))
+	defer cancel()
 
 	stepFromResource, bucketID, testID, err := createStepFromResourceData(d)
 	if err != nil {
 		return fmt.Errorf("Failed to read step from resource data: %s", err)
 	}
 
-	step, err := client.ReadTestStep(stepFromResource, bucketID, testID)
+	step, err := client.ReadTestStep(ctx, stepFromResource, bucketID, testID)
 	if err != nil {
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "403") {
+		if runscope.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
@@ -180,24 +349,7 @@ func resourceStepRead(d *schema.ResourceData, meta interface{}) error {
 
 	d.Set("bucket_id", bucketID)
 	d.Set("test_id", testID)
-	d.Set("step_type", step.StepType)
-	d.Set("method", step.Method)
-	d.Set("url", step.URL)
-	d.Set("body", step.Body)
-	d.Set("variables", readVariables(step.Variables))
-	d.Set("assertions", readAssertions(step.Assertions))
-	d.Set("headers", readHeaders(step.Headers))
-	d.Set("scripts", step.Scripts)
-	d.Set("before_scripts", step.BeforeScripts)
-	if step.Auth != nil && len(step.Auth) > 0 {
-		d.Set("auth", []interface{}{
-			map[string]interface{}{
-				"username":  step.Auth["username"],
-				"auth_type": step.Auth["auth_type"],
-				"password":  step.Auth["password"],
-			},
-		})
-	}
+	setStepResourceData(d, step)
 
 	return nil
 }
@@ -209,31 +361,44 @@ func resourceStepUpdate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Error updating step: %s", err)
 	}
 
-	if d.HasChange("url") ||
+	if d.HasChange("step_type") ||
+		d.HasChange("method") ||
+		d.HasChange("url") ||
 		d.HasChange("variables") ||
 		d.HasChange("assertions") ||
 		d.HasChange("headers") ||
-		d.HasChange("body") {
-		client := meta.(*runscope.Client)
-		_, err = client.UpdateTestStep(stepFromResource, bucketID, testID)
+		d.HasChange("auth") ||
+		d.HasChange("body") ||
+		d.HasChange("scripts") ||
+		d.HasChange("before_scripts") ||
+		d.HasChange("condition") ||
+		d.HasChange("subtest") ||
+		d.HasChange("pause") ||
+		d.HasChange("ghost_inspector") {
+		client := meta.(*runscopeClient)
+		ctx, cancel := client.newContext(d.Timeout(schema.TimeoutUpdate))
+		defer cancel()
+		_, err = client.UpdateTestStep(ctx, stepFromResource, bucketID, testID)
 
 		if err != nil {
 			return fmt.Errorf("Error updating step: %s", err)
 		}
 	}
 
-	return nil
+	return resourceStepRead(d, meta)
 }
 
 func resourceStepDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutDelete))
+	defer cancel()
 
 	stepFromResource, bucketID, testID, err := createStepFromResourceData(d)
 	if err != nil {
 		return fmt.Errorf("Failed to read step from resource data: %s", err)
 	}
 
-	err = client.DeleteTestStep(stepFromResource, bucketID, testID)
+	err = client.DeleteTestStep(ctx, stepFromResource, bucketID, testID)
 	if err != nil {
 		return fmt.Errorf("Error deleting step: %s", err)
 	}
@@ -241,88 +406,268 @@ func resourceStepDelete(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func createStepFromResourceData(d *schema.ResourceData) (*runscope.TestStep, string, string, error) {
+func resourceStepCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !stepValuesKnown(d, "") {
+		return nil
+	}
 
-	step := runscope.NewTestStep()
+	attributes := map[string]interface{}{}
+	for key := range stepSchema(map[string]*schema.Schema{}) {
+		attributes[key] = d.Get(key)
+	}
+
+	return validateStep(expandStep(attributes))
+}
+
+func resourceStepImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts, err := parseImportID(d.Id(), "bucket_key/test_id/step_id")
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("bucket_id", parts[0])
+	d.Set("test_id", parts[1])
+	d.SetId(parts[2])
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func createStepFromResourceData(d *schema.ResourceData) (*runscope.TestStep, string, string, error) {
 	bucketID := d.Get("bucket_id").(string)
 	testID := d.Get("test_id").(string)
+
+	attributes := map[string]interface{}{}
+	for key := range stepSchema(map[string]*schema.Schema{}) {
+		attributes[key] = d.Get(key)
+	}
+
+	step := expandStep(attributes)
 	step.ID = d.Id()
-	step.StepType = d.Get("step_type").(string)
-	step.Body = d.Get("body").(string)
-	if attr, ok := d.GetOk("method"); ok {
-		step.Method = attr.(string)
+
+	return step, bucketID, testID, nil
+}
+
+// expandStep creates a step from its attributes, either read from a
+// runscope_step resource or a step block of a runscope_test resource
+func expandStep(attributes map[string]interface{}) *runscope.TestStep {
+	step := runscope.NewTestStep()
+
+	// Collections that belong to the type of step are sent even when empty,
+	// so that removing them from the configuration clears them on update
+	step.Variables = []*runscope.Variable{}
+	step.Assertions = []*runscope.Assertion{}
+	step.Headers = map[string][]string{}
+	step.Auth = map[string]string{}
+	step.Scripts = []string{}
+	step.BeforeScripts = []string{}
+
+	step.StepType = attributes["step_type"].(string)
+	step.Method = attributes["method"].(string)
+	step.URL = attributes["url"].(string)
+	step.Body = attributes["body"].(string)
+
+	for _, x := range attributes["variables"].(*schema.Set).List() {
+		item := x.(map[string]interface{})
+		step.Variables = append(step.Variables, &runscope.Variable{
+			Name:     item["name"].(string),
+			Property: item["property"].(string),
+			Source:   item["source"].(string),
+		})
 	}
 
-	if attr, ok := d.GetOk("url"); ok {
-		step.URL = attr.(string)
+	if authSet := attributes["auth"].(*schema.Set).List(); len(authSet) == 1 {
+		for key, value := range authSet[0].(map[string]interface{}) {
+			step.Auth[key] = value.(string)
+		}
 	}
 
-	if attr, ok := d.GetOk("variables"); ok {
-		variables := []*runscope.Variable{}
-		items := attr.(*schema.Set)
-		for _, x := range items.List() {
-			item := x.(map[string]interface{})
-			variable := runscope.Variable{
-				Name:     item["name"].(string),
-				Property: item["property"].(string),
-				Source:   item["source"].(string),
-			}
+	for _, x := range attributes["assertions"].([]interface{}) {
+		item := x.(map[string]interface{})
+		step.Assertions = append(step.Assertions, &runscope.Assertion{
+			Source:     item["source"].(string),
+			Property:   item["property"].(string),
+			Comparison: item["comparison"].(string),
+			Value:      item["value"].(string),
+		})
+	}
+
+	for _, x := range attributes["headers"].(*schema.Set).List() {
+		item := x.(map[string]interface{})
+		header := item["header"].(string)
+		step.Headers[header] = append(step.Headers[header], item["value"].(string))
+	}
+
+	step.Scripts = append(step.Scripts, expandStringList(attributes["scripts"].([]interface{}))...)
+	step.BeforeScripts = append(step.BeforeScripts, expandStringList(attributes["before_scripts"].([]interface{}))...)
+
+	if condition := attributes["condition"].([]interface{}); len(condition) == 1 {
+		item := condition[0].(map[string]interface{})
+		step.LeftValue = item["left_value"].(string)
+		step.Comparison = item["comparison"].(string)
+		step.RightValue = item["right_value"].(string)
+	}
+
+	if subtest := attributes["subtest"].([]interface{}); len(subtest) == 1 {
+		item := subtest[0].(map[string]interface{})
+		step.TestUUID = item["test_uuid"].(string)
+		step.BucketKey = item["bucket_key"].(string)
+		step.EnvironmentUUID = item["environment_uuid"].(string)
+		step.Params = expandSubtestParams(item["params"].(map[string]interface{}))
+	}
+
+	if pause := attributes["pause"].([]interface{}); len(pause) == 1 {
+		step.Duration = pause[0].(map[string]interface{})["duration"].(int)
+	}
+
+	if ghostInspector := attributes["ghost_inspector"].([]interface{}); len(ghostInspector) == 1 {
+		item := ghostInspector[0].(map[string]interface{})
+		step.GhostTestID = item["test_id"].(string)
+		step.StartURL = item["start_url"].(string)
+	}
+
+	return step
+}
+
+func expandSubtestParams(params map[string]interface{}) []*runscope.SubtestParam {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	result := make([]*runscope.SubtestParam, 0, len(params))
+	for _, name := range names {
+		result = append(result, &runscope.SubtestParam{Name: name, Value: params[name].(string)})
+	}
+
+	return result
+}
 
-			variables = append(variables, &variable)
+// validateStep checks the attributes set are valid for the type of step and each other
+func validateStep(step *runscope.TestStep) error {
+	if step.StepType == "request" {
+		if step.Method == "" {
+			return fmt.Errorf("A request step must set a method")
 		}
 
-		step.Variables = variables
+		if step.Method == "GET" && step.Body != "" {
+			return fmt.Errorf("A request step using the GET method can not set a body")
+		}
 	}
 
-	if v, _ := d.GetOk("auth"); v != nil {
-		authSet := v.(*schema.Set).List()
-		if len(authSet) == 1 {
-			authMap := authSet[0].(map[string]interface{})
-			auth := make(map[string]string)
-			for key, value := range authMap {
-				auth[key] = value.(string)
-			}
-			step.Auth = auth
+	typed := false
+	for _, t := range typedStepTypes {
+		switch {
+		case t.stepType == step.StepType && !t.set(step):
+			return fmt.Errorf("A %s step must include a %s block", t.stepType, t.block)
+		case t.stepType != step.StepType && t.set(step):
+			return fmt.Errorf("A %s block can only be set on a %s step, not a %s step", t.block, t.stepType, step.StepType)
 		}
+
+		typed = typed || t.stepType == step.StepType
 	}
 
-	if attr, ok := d.GetOk("assertions"); ok {
-		assertions := []*runscope.Assertion{}
-		items := attr.([]interface{})
-		for _, x := range items {
-			item := x.(map[string]interface{})
-			variable := runscope.Assertion{
-				Source:     item["source"].(string),
-				Property:   item["property"].(string),
-				Comparison: item["comparison"].(string),
-				Value:      item["value"].(string),
-			}
+	for _, variable := range step.Variables {
+		if variable.Property == "" && contains(sourcesWithProperty, variable.Source) {
+			return fmt.Errorf("Variable %s with source %s must set a property", variable.Name, variable.Source)
+		}
+	}
 
-			assertions = append(assertions, &variable)
+	for i, assertion := range step.Assertions {
+		if assertion.Property == "" && contains(sourcesWithProperty, assertion.Source) {
+			return fmt.Errorf("Assertion %d with source %s must set a property", i, assertion.Source)
 		}
 
-		step.Assertions = assertions
+		if (assertion.Value == nil || assertion.Value == "") && !contains(comparisonsWithoutValue, assertion.Comparison) {
+			return fmt.Errorf("Assertion %d with comparison %s must set a value", i, assertion.Comparison)
+		}
+	}
+
+	if step.StepType == "condition" && step.RightValue == "" && !contains(comparisonsWithoutValue, step.Comparison) {
+		return fmt.Errorf("A condition with comparison %s must set a right_value", step.Comparison)
+	}
+
+	if !typed {
+		return nil
 	}
 
-	if attr, ok := d.GetOk("headers"); ok {
-		step.Headers = make(map[string][]string)
-		items := attr.(*schema.Set)
-		for _, x := range items.List() {
-			item := x.(map[string]interface{})
-			header := item["header"].(string)
-			step.Headers[header] = append(step.Headers[header], item["value"].(string))
+	if step.Method != "" || step.URL != "" || len(step.Headers) > 0 || len(step.Auth) > 0 ||
+		step.Body != "" || len(step.Scripts) > 0 || len(step.BeforeScripts) > 0 {
+		return fmt.Errorf("A %s step can not set the request step attributes: %s",
+			step.StepType, strings.Join(requestStepAttributes, ", "))
+	}
+
+	if (step.StepType == "condition" || step.StepType == "pause") &&
+		(len(step.Variables) > 0 || len(step.Assertions) > 0) {
+		return fmt.Errorf("A %s step can not set variables or assertions", step.StepType)
+	}
+
+	return nil
+}
+
+// stepValuesKnown returns false when a value of the step attributes under prefix is only
+// known when applying, validateStep then runs when the diff is made again before applying
+func stepValuesKnown(d *schema.ResourceDiff, prefix string) bool {
+	for key := range stepSchema(map[string]*schema.Schema{}) {
+		if !valueKnown(d, prefix+key) {
+			return false
 		}
 	}
 
-	if attr, ok := d.GetOk("scripts"); ok {
-		step.Scripts = expandStringList(attr.([]interface{}))
+	return true
+}
+
+// valueKnown returns false when the value of key, or any value within a list or map, is
+// only known when applying. Values within sets are read as their interpolation instead.
+func valueKnown(d *schema.ResourceDiff, key string) bool {
+	if !d.NewValueKnown(key) {
+		return false
+	}
+
+	switch value := d.Get(key).(type) {
+	case []interface{}:
+		for i := range value {
+			if !valueKnown(d, fmt.Sprintf("%s.%d", key, i)) {
+				return false
+			}
+		}
+	case map[string]interface{}:
+		for k := range value {
+			if !valueKnown(d, key+"."+k) {
+				return false
+			}
+		}
 	}
 
-	if attr, ok := d.GetOk("before_scripts"); ok {
-		step.BeforeScripts = expandStringList(attr.([]interface{}))
+	return true
+}
+
+func setStepResourceData(d *schema.ResourceData, step *runscope.TestStep) {
+	for key, value := range flattenStep(step) {
+		d.Set(key, value)
 	}
+}
 
-	return step, bucketID, testID, nil
+// flattenStep reads the attributes of a step, nested sets are returned as
+// *schema.Set so they can be set within the step blocks of a runscope_test
+func flattenStep(step *runscope.TestStep) map[string]interface{} {
+	s := stepSchema(map[string]*schema.Schema{})
+	return map[string]interface{}{
+		"step_type":       step.StepType,
+		"method":          step.Method,
+		"url":             step.URL,
+		"body":            step.Body,
+		"variables":       newSet(s["variables"], readVariables(step.Variables)),
+		"assertions":      readAssertions(step.Assertions),
+		"headers":         newSet(s["headers"], readHeaders(step.Headers)),
+		"scripts":         step.Scripts,
+		"before_scripts":  step.BeforeScripts,
+		"auth":            newSet(s["auth"], readAuth(step.Auth)),
+		"condition":       readCondition(step),
+		"subtest":         readSubtest(step),
+		"pause":           readPause(step),
+		"ghost_inspector": readGhostInspector(step),
+	}
 }
 
 func readVariables(variables []*runscope.Variable) []map[string]interface{} {
@@ -359,11 +704,81 @@ func readAssertions(assertions []*runscope.Assertion) []map[string]interface{} {
 }
 
 func readHeaders(headers map[string][]string) []map[string]interface{} {
-	result := make([]map[string]interface{}, len(headers))
-	for key, header := range headers {
+	result := make([]map[string]interface{}, 0, len(headers))
+	for key, values := range headers {
+		for _, value := range values {
+			result = append(result, map[string]interface{}{
+				"header": key,
+				"value":  value,
+			})
+		}
+	}
+
+	return result
+}
+
+func readAuth(auth map[string]string) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, 1)
+	if len(auth) > 0 {
+		result = append(result, map[string]interface{}{
+			"username":  auth["username"],
+			"auth_type": auth["auth_type"],
+			"password":  auth["password"],
+		})
+	}
+
+	return result
+}
+
+func readCondition(step *runscope.TestStep) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, 1)
+	if step.Comparison != "" {
+		result = append(result, map[string]interface{}{
+			"left_value":  step.LeftValue,
+			"comparison":  step.Comparison,
+			"right_value": step.RightValue,
+		})
+	}
+
+	return result
+}
+
+func readSubtest(step *runscope.TestStep) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, 1)
+	if step.TestUUID != "" {
+		params := map[string]interface{}{}
+		for _, param := range step.Params {
+			params[param.Name] = param.Value
+		}
+
+		result = append(result, map[string]interface{}{
+			"test_uuid":        step.TestUUID,
+			"bucket_key":       step.BucketKey,
+			"environment_uuid": step.EnvironmentUUID,
+			"params":           params,
+		})
+	}
+
+	return result
+}
+
+func readPause(step *runscope.TestStep) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, 1)
+	if step.StepType == "pause" {
+		result = append(result, map[string]interface{}{
+			"duration": step.Duration,
+		})
+	}
+
+	return result
+}
+
+func readGhostInspector(step *runscope.TestStep) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, 1)
+	if step.GhostTestID != "" {
 		result = append(result, map[string]interface{}{
-			"header": key,
-			"value":  header,
+			"test_id":   step.GhostTestID,
+			"start_url": step.StartURL,
 		})
 	}
 
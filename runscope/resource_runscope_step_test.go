@@ -1,18 +1,24 @@
 package runscope
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"regexp"
 	"testing"
 
 	"github.com/ewilde/go-runscope"
+	tfconfig "github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
 
 func TestAccStep_basic(t *testing.T) {
 	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckStepDestroy,
@@ -25,13 +31,19 @@ func TestAccStep_basic(t *testing.T) {
 						"runscope_step.main_page", "url", "http://example.com"),
 				),
 			},
+			{
+				ResourceName:      "runscope_step.main_page",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("runscope_step.main_page", "bucket_id", "test_id"),
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
 
 func TestAccStep_multiple_steps(t *testing.T) {
 	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckStepDestroy,
@@ -49,8 +61,367 @@ func TestAccStep_multiple_steps(t *testing.T) {
 	})
 }
 
+func TestAccStep_update(t *testing.T) {
+	var stepID string
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStepDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testRunscopeStepConfigA, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStepMainPageExists("runscope_step.main_page"),
+					testAccCheckStepID("runscope_step.main_page", &stepID, false)),
+			},
+			{
+				Config: fmt.Sprintf(testRunscopeStepConfigUpdate, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStepExists("runscope_step.main_page"),
+					testAccCheckStepID("runscope_step.main_page", &stepID, true),
+					resource.TestCheckResourceAttr(
+						"runscope_step.main_page", "method", "POST"),
+					resource.TestCheckResourceAttr(
+						"runscope_step.main_page", "url", "http://example.com/update"),
+					resource.TestCheckResourceAttr(
+						"runscope_step.main_page", "body", "{ \"update\": true }"),
+					resource.TestCheckResourceAttr(
+						"runscope_step.main_page", "variables.#", "1"),
+					resource.TestCheckResourceAttr(
+						"runscope_step.main_page", "assertions.#", "1"),
+					resource.TestCheckResourceAttr(
+						"runscope_step.main_page", "assertions.0.value", "201"),
+					resource.TestCheckResourceAttr(
+						"runscope_step.main_page", "headers.#", "1"),
+					resource.TestCheckResourceAttr(
+						"runscope_step.main_page", "auth.#", "1"),
+					resource.TestCheckResourceAttr(
+						"runscope_step.main_page", "scripts.#", "1"),
+					resource.TestCheckResourceAttr(
+						"runscope_step.main_page", "scripts.0", "log(\"script updated\");"),
+					resource.TestCheckResourceAttr(
+						"runscope_step.main_page", "before_scripts.#", "0")),
+			},
+		},
+	})
+}
+
+func TestAccStep_condition(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStepDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testRunscopeStepConfigConditionConflicts, teamID),
+				ExpectError: regexp.MustCompile("conflicts with method"),
+			},
+			{
+				Config: fmt.Sprintf(testRunscopeStepConfigCondition, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStepExists("runscope_step.condition"),
+					resource.TestCheckResourceAttr("runscope_step.condition", "condition.#", "1"),
+					resource.TestCheckResourceAttr("runscope_step.condition", "condition.0.left_value", "{{status}}"),
+					resource.TestCheckResourceAttr("runscope_step.condition", "condition.0.comparison", "equal_number"),
+					resource.TestCheckResourceAttr("runscope_step.condition", "condition.0.right_value", "200"),
+					resource.TestCheckResourceAttr("runscope_step.condition", "url", ""),
+				),
+			},
+			{
+				ResourceName:      "runscope_step.condition",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("runscope_step.condition", "bucket_id", "test_id"),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccStep_subtest(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStepDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testRunscopeStepConfigSubtest, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStepExists("runscope_step.subtest"),
+					resource.TestCheckResourceAttr("runscope_step.subtest", "subtest.#", "1"),
+					resource.TestCheckResourceAttrPair("runscope_step.subtest", "subtest.0.test_uuid", "runscope_test.login", "id"),
+					resource.TestCheckResourceAttrPair("runscope_step.subtest", "subtest.0.environment_uuid", "runscope_test.login", "default_environment_id"),
+					resource.TestCheckResourceAttr("runscope_step.subtest", "subtest.0.params.%", "2"),
+					resource.TestCheckResourceAttr("runscope_step.subtest", "subtest.0.params.username", "terraform"),
+					resource.TestCheckResourceAttr("runscope_step.subtest", "variables.#", "1"),
+					resource.TestCheckResourceAttr("runscope_step.subtest", "assertions.0.source", "response_json"),
+				),
+			},
+			{
+				ResourceName:      "runscope_step.subtest",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("runscope_step.subtest", "bucket_id", "test_id"),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccStep_pause_ghost_inspector(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckStepDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testRunscopeStepConfigPauseGhostInspector, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStepExists("runscope_step.pause"),
+					resource.TestCheckResourceAttr("runscope_step.pause", "pause.#", "1"),
+					resource.TestCheckResourceAttr("runscope_step.pause", "pause.0.duration", "5"),
+					testAccCheckStepExists("runscope_step.ghost_inspector"),
+					resource.TestCheckResourceAttr("runscope_step.ghost_inspector", "ghost_inspector.#", "1"),
+					resource.TestCheckResourceAttr("runscope_step.ghost_inspector", "ghost_inspector.0.test_id", "5a1e5e1a3b7c0d0f3c9e8f21"),
+					resource.TestCheckResourceAttr("runscope_step.ghost_inspector", "ghost_inspector.0.start_url", "https://example.com"),
+					resource.TestCheckResourceAttr("runscope_step.ghost_inspector", "assertions.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "runscope_step.pause",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("runscope_step.pause", "bucket_id", "test_id"),
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      "runscope_step.ghost_inspector",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("runscope_step.ghost_inspector", "bucket_id", "test_id"),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestValidateStep(t *testing.T) {
+	status := &runscope.Assertion{Source: "response_status", Comparison: "equal_number", Value: "200"}
+	cases := []struct {
+		step      runscope.TestStep
+		expectErr bool
+	}{
+		{runscope.TestStep{StepType: "request", Method: "GET", URL: "http://example.com"}, false},
+		{runscope.TestStep{StepType: "request", URL: "http://example.com"}, true},
+		{runscope.TestStep{StepType: "request", Method: "GET", URL: "http://example.com", Body: "{}"}, true},
+		{runscope.TestStep{StepType: "request", Method: "POST", URL: "http://example.com", Body: "{}"}, false},
+		{runscope.TestStep{StepType: "condition", LeftValue: "{{a}}", Comparison: "equal", RightValue: "b"}, false},
+		{runscope.TestStep{StepType: "condition"}, true},
+		{runscope.TestStep{StepType: "condition", Comparison: "equal", URL: "http://example.com"}, true},
+		{runscope.TestStep{StepType: "condition", Comparison: "equal", Headers: map[string][]string{"a": {"b"}}}, true},
+		{runscope.TestStep{StepType: "condition", Comparison: "equal", Assertions: []*runscope.Assertion{{}}}, true},
+		{runscope.TestStep{StepType: "request", Method: "GET", Comparison: "equal"}, true},
+		{runscope.TestStep{StepType: "subtest", TestUUID: "test", Assertions: []*runscope.Assertion{status}}, false},
+		{runscope.TestStep{StepType: "subtest"}, true},
+		{runscope.TestStep{StepType: "subtest", TestUUID: "test", Method: "GET"}, true},
+		{runscope.TestStep{StepType: "subtest", TestUUID: "test", Comparison: "equal"}, true},
+		{runscope.TestStep{StepType: "pause", Duration: 5}, false},
+		{runscope.TestStep{StepType: "pause"}, true},
+		{runscope.TestStep{StepType: "pause", Duration: 5, Variables: []*runscope.Variable{{}}}, true},
+		{runscope.TestStep{StepType: "ghost-inspector", GhostTestID: "test", Assertions: []*runscope.Assertion{status}}, false},
+		{runscope.TestStep{StepType: "ghost-inspector", GhostTestID: "test", Body: "{}"}, true},
+		{runscope.TestStep{StepType: "request", Method: "GET", Duration: 5}, true},
+		{runscope.TestStep{StepType: "request", Method: "GET", Assertions: []*runscope.Assertion{
+			{Source: "response_json", Comparison: "equal", Value: "1"}}}, true},
+		{runscope.TestStep{StepType: "request", Method: "GET", Assertions: []*runscope.Assertion{
+			{Source: "response_json", Property: "data.id", Comparison: "equal", Value: "1"}}}, false},
+		{runscope.TestStep{StepType: "request", Method: "GET", Assertions: []*runscope.Assertion{
+			{Source: "response_status", Comparison: "equal_number"}}}, true},
+		{runscope.TestStep{StepType: "request", Method: "GET", Assertions: []*runscope.Assertion{
+			{Source: "response_json", Property: "data.id", Comparison: "not_empty"}}}, false},
+		{runscope.TestStep{StepType: "request", Method: "GET", Variables: []*runscope.Variable{
+			{Name: "encoding", Source: "response_header"}}}, true},
+		{runscope.TestStep{StepType: "request", Method: "GET", Variables: []*runscope.Variable{
+			{Name: "encoding", Source: "response_header", Property: "Content-Encoding"}}}, false},
+		{runscope.TestStep{StepType: "condition", LeftValue: "{{a}}", Comparison: "equal"}, true},
+		{runscope.TestStep{StepType: "condition", LeftValue: "{{a}}", Comparison: "is_empty"}, false},
+	}
+
+	for i, c := range cases {
+		err := validateStep(&c.step)
+		if (err != nil) != c.expectErr {
+			t.Fatalf("%d: expected error %t, got %v", i, c.expectErr, err)
+		}
+	}
+}
+
+func TestStepResourceDataRoundTrip(t *testing.T) {
+	raw := map[string]interface{}{
+		"bucket_id": "bucket",
+		"test_id":   "test",
+		"step_type": "request",
+		"method":    "POST",
+		"url":       "http://example.com",
+		"body":      "{}",
+		"variables": []interface{}{
+			map[string]interface{}{"name": "httpStatus", "source": "response_status"},
+		},
+		"assertions": []interface{}{
+			map[string]interface{}{"source": "response_status", "comparison": "equal_number", "value": "200"},
+			map[string]interface{}{"source": "response_json", "comparison": "equal", "property": "data.id", "value": "1"},
+		},
+		"headers": []interface{}{
+			map[string]interface{}{"header": "Accept-Encoding", "value": "application/json"},
+			map[string]interface{}{"header": "Accept-Encoding", "value": "application/xml"},
+		},
+		"auth": []interface{}{
+			map[string]interface{}{"username": "user", "auth_type": "basic", "password": "password1"},
+		},
+		"scripts":        []interface{}{"log(\"script 1\");", "log(\"script 2\");"},
+		"before_scripts": []interface{}{"log(\"before script\");"},
+	}
+
+	stepSchema := resourceRunscopeStep().Schema
+	expected := schema.TestResourceDataRaw(t, stepSchema, raw)
+	step, _, _, err := createStepFromResourceData(expected)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	actual := schema.TestResourceDataRaw(t, stepSchema, map[string]interface{}{
+		"bucket_id": "bucket",
+		"test_id":   "test",
+		"step_type": "request",
+	})
+	setStepResourceData(actual, step)
+
+	for key := range stepSchema {
+		if !testResourceDataValueEqual(actual.Get(key), expected.Get(key)) {
+			t.Fatalf("Expected %s to round trip\n\nGot:\n\n%#v\n\nExpected:\n\n%#v\n",
+				key, actual.Get(key), expected.Get(key))
+		}
+	}
+}
+
+func TestStepValidation_plan(t *testing.T) {
+	assertion := func(source string, property string, comparison string, value string) interface{} {
+		return map[string]interface{}{"source": source, "property": property, "comparison": comparison, "value": value}
+	}
+
+	step := map[string]interface{}{
+		"step_type": "request", "method": "GET", "url": "http://example.com",
+		"assertions": []interface{}{assertion("response_json", "", "equal", "ok")},
+	}
+
+	cases := []struct {
+		resource *schema.Resource
+		raw      map[string]interface{}
+		expected string
+	}{
+		{
+			resourceRunscopeStep(),
+			map[string]interface{}{"bucket_id": "bucket", "test_id": "test", "step_type": "request", "method": "GET",
+				"assertions": []interface{}{assertion("response_json", "", "equal", "ok")}},
+			"Assertion 0 with source response_json must set a property",
+		},
+		{
+			resourceRunscopeStep(),
+			map[string]interface{}{"bucket_id": "bucket", "test_id": "test", "step_type": "request", "method": "GET",
+				"assertions": []interface{}{assertion("response_status", "", "equal_number", "")}},
+			"Assertion 0 with comparison equal_number must set a value",
+		},
+		{
+			resourceRunscopeStep(),
+			map[string]interface{}{"bucket_id": "bucket", "test_id": "test", "step_type": "request", "method": "GET",
+				"body": "{}"},
+			"A request step using the GET method can not set a body",
+		},
+		{
+			resourceRunscopeStep(),
+			map[string]interface{}{"bucket_id": "bucket", "test_id": "test", "step_type": "request"},
+			"A request step must set a method",
+		},
+		{
+			// the property is only known when applying, so the step is checked then
+			resourceRunscopeStep(),
+			map[string]interface{}{"bucket_id": "bucket", "test_id": "test", "step_type": "request", "method": "GET",
+				"assertions": []interface{}{assertion("response_json", "${var.property}", "equal", "ok")}},
+			"",
+		},
+		{
+			resourceRunscopeTest(),
+			map[string]interface{}{"bucket_id": "bucket", "name": "test", "description": "test",
+				"step": []interface{}{step}},
+			"Invalid step 0: Assertion 0 with source response_json must set a property",
+		},
+	}
+
+	for i, c := range cases {
+		rawConfig, err := tfconfig.NewRawConfig(c.raw)
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		config := terraform.NewResourceConfig(rawConfig)
+		if _, errors := c.resource.Validate(config); len(errors) > 0 {
+			t.Fatalf("%d: expected the configuration to pass schema validation, got %v", i, errors)
+		}
+
+		_, err = c.resource.Diff(nil, config, &runscopeClient{})
+		if c.expected == "" {
+			if err != nil {
+				t.Errorf("%d: expected no error until the values are known, actual %s", i, err)
+			}
+		} else if err == nil || err.Error() != c.expected {
+			t.Errorf("%d: expected error %q, actual %v", i, c.expected, err)
+		}
+	}
+}
+
+func TestCreateTestStep_notAddedLast(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"meta": {"status": "success"}, "data": [{"id": "existing", "step_type": "request"}]}`))
+		case "POST":
+			// the new step is added at the start of the test rather than the end
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"meta": {"status": "success"}, "data": [` +
+				`{"id": "created", "step_type": "pause", "duration": 5}, {"id": "existing", "step_type": "request"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := runscope.NewClient(server.URL, "token")
+	step, err := client.CreateTestStep(context.Background(), &runscope.TestStep{StepType: "pause", Duration: 5}, "bucket", "test")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if step.ID != "created" {
+		t.Errorf("Expected the created step to be returned, actual step %s", step.ID)
+	}
+}
+
+func testAccCheckStepID(n string, id *string, same bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if same && rs.Primary.ID != *id {
+			return fmt.Errorf("Expected step %s to be updated in place, actual %s", *id, rs.Primary.ID)
+		}
+
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
 func testAccCheckStepDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*runscope.Client)
+	client := testAccProvider.Meta().(*runscopeClient)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "runscope_step" {
@@ -60,7 +431,7 @@ func testAccCheckStepDestroy(s *terraform.State) error {
 		var err error
 		bucketID := rs.Primary.Attributes["bucket_id"]
 		testID := rs.Primary.Attributes["test_id"]
-		err = client.DeleteTestStep(&runscope.TestStep{ID: rs.Primary.ID}, bucketID, testID)
+		err = client.DeleteTestStep(context.Background(), &runscope.TestStep{ID: rs.Primary.ID}, bucketID, testID)
 
 		if err == nil {
 			return fmt.Errorf("Record %s still exists", rs.Primary.ID)
@@ -82,7 +453,7 @@ func testAccCheckStepMainPageExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No Record ID is set")
 		}
 
-		client := testAccProvider.Meta().(*runscope.Client)
+		client := testAccProvider.Meta().(*runscopeClient)
 
 		var foundRecord *runscope.TestStep
 		var err error
@@ -92,7 +463,7 @@ func testAccCheckStepMainPageExists(n string) resource.TestCheckFunc {
 		bucketID := rs.Primary.Attributes["bucket_id"]
 		testID := rs.Primary.Attributes["test_id"]
 
-		foundRecord, err = client.ReadTestStep(step, bucketID, testID)
+		foundRecord, err = client.ReadTestStep(context.Background(), step, bucketID, testID)
 
 		if err != nil {
 			return err
@@ -172,7 +543,7 @@ func testAccCheckStepExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No Record ID is set")
 		}
 
-		client := testAccProvider.Meta().(*runscope.Client)
+		client := testAccProvider.Meta().(*runscopeClient)
 
 		var foundRecord *runscope.TestStep
 		var err error
@@ -182,7 +553,7 @@ func testAccCheckStepExists(n string) resource.TestCheckFunc {
 		bucketID := rs.Primary.Attributes["bucket_id"]
 		testID := rs.Primary.Attributes["test_id"]
 
-		foundRecord, err = client.ReadTestStep(step, bucketID, testID)
+		foundRecord, err = client.ReadTestStep(context.Background(), step, bucketID, testID)
 
 		if err != nil {
 			return err
@@ -296,3 +667,222 @@ resource "runscope_bucket" "bucket" {
   team_uuid = "%s"
 }
 `
+
+const testRunscopeStepConfigUpdate = `
+resource "runscope_step" "main_page" {
+  bucket_id      = "${runscope_bucket.bucket.id}"
+  test_id        = "${runscope_test.test.id}"
+  step_type      = "request"
+  url            = "http://example.com/update"
+  method         = "POST"
+  body           = "{ \"update\": true }"
+  variables      = [
+  	{
+  	   name     = "httpStatus"
+  	   source   = "response_status"
+  	},
+  ]
+  assertions     = [
+  	{
+  	   source     = "response_status"
+           comparison = "equal_number"
+           value      = "201"
+  	},
+  ]
+  headers        = [
+  	{
+  		header = "Content-Type",
+  		value  = "application/json"
+  	},
+  ]
+
+  auth = {
+	username  = "updated-user"
+	auth_type = "basic"
+	password  = "password2"
+  }
+
+  scripts = [
+    "log(\"script updated\");"
+  ]
+}
+
+resource "runscope_test" "test" {
+  bucket_id   = "${runscope_bucket.bucket.id}"
+  name        = "runscope test"
+  description = "This is a test test..."
+}
+
+resource "runscope_bucket" "bucket" {
+  name      = "terraform-provider-test"
+  team_uuid = "%s"
+}
+`
+
+const testRunscopeStepConfigCondition = `
+resource "runscope_step" "request" {
+  bucket_id      = "${runscope_bucket.bucket.id}"
+  test_id        = "${runscope_test.test.id}"
+  step_type      = "request"
+  url            = "http://example.com"
+  method         = "GET"
+  variables      = [
+    {
+      name   = "status"
+      source = "response_status"
+    }
+  ]
+}
+
+resource "runscope_step" "condition" {
+  bucket_id      = "${runscope_bucket.bucket.id}"
+  test_id        = "${runscope_test.test.id}"
+  step_type      = "condition"
+  condition {
+    left_value  = "{{status}}"
+    comparison  = "equal_number"
+    right_value = "200"
+  }
+
+  depends_on = ["runscope_step.request"]
+}
+
+resource "runscope_test" "test" {
+  bucket_id   = "${runscope_bucket.bucket.id}"
+  name        = "runscope test"
+  description = "This is a test with a condition"
+}
+
+resource "runscope_bucket" "bucket" {
+  name      = "terraform-provider-test"
+  team_uuid = "%s"
+}
+`
+
+const testRunscopeStepConfigConditionConflicts = `
+resource "runscope_step" "condition" {
+  bucket_id      = "${runscope_bucket.bucket.id}"
+  test_id        = "${runscope_test.test.id}"
+  step_type      = "condition"
+  method         = "GET"
+  condition {
+    left_value  = "{{status}}"
+    comparison  = "equal_number"
+    right_value = "200"
+  }
+}
+
+resource "runscope_test" "test" {
+  bucket_id   = "${runscope_bucket.bucket.id}"
+  name        = "runscope test"
+  description = "This is a test with a condition"
+}
+
+resource "runscope_bucket" "bucket" {
+  name      = "terraform-provider-test"
+  team_uuid = "%s"
+}
+`
+
+const testRunscopeStepConfigSubtest = `
+resource "runscope_step" "login" {
+  bucket_id      = "${runscope_bucket.bucket.id}"
+  test_id        = "${runscope_test.login.id}"
+  step_type      = "request"
+  url            = "http://example.com/login"
+  method         = "POST"
+  body           = "{\"username\": \"{{username}}\", \"password\": \"{{password}}\"}"
+}
+
+resource "runscope_step" "subtest" {
+  bucket_id      = "${runscope_bucket.bucket.id}"
+  test_id        = "${runscope_test.test.id}"
+  step_type      = "subtest"
+  subtest {
+    test_uuid        = "${runscope_test.login.id}"
+    bucket_key       = "${runscope_bucket.bucket.id}"
+    environment_uuid = "${runscope_test.login.default_environment_id}"
+    params {
+      username = "terraform"
+      password = "secret"
+    }
+  }
+
+  variables = [
+    {
+      name     = "token"
+      source   = "response_json"
+      property = "token"
+    }
+  ]
+
+  assertions = [
+    {
+      source     = "response_json"
+      property   = "result"
+      comparison = "equal"
+      value      = "pass"
+    }
+  ]
+}
+
+resource "runscope_test" "login" {
+  bucket_id   = "${runscope_bucket.bucket.id}"
+  name        = "login"
+  description = "A shared login flow"
+}
+
+resource "runscope_test" "test" {
+  bucket_id   = "${runscope_bucket.bucket.id}"
+  name        = "runscope test"
+  description = "This is a test calling the login test"
+}
+
+resource "runscope_bucket" "bucket" {
+  name      = "terraform-provider-test"
+  team_uuid = "%s"
+}
+`
+
+const testRunscopeStepConfigPauseGhostInspector = `
+resource "runscope_step" "pause" {
+  bucket_id      = "${runscope_bucket.bucket.id}"
+  test_id        = "${runscope_test.test.id}"
+  step_type      = "pause"
+  pause {
+    duration = 5
+  }
+}
+
+resource "runscope_step" "ghost_inspector" {
+  bucket_id      = "${runscope_bucket.bucket.id}"
+  test_id        = "${runscope_test.test.id}"
+  step_type      = "ghost-inspector"
+  ghost_inspector {
+    test_id   = "5a1e5e1a3b7c0d0f3c9e8f21"
+    start_url = "https://example.com"
+  }
+
+  assertions = [
+    {
+      source     = "response_json"
+      property   = "passing"
+      comparison = "equal"
+      value      = "true"
+    }
+  ]
+
+  depends_on = ["runscope_step.pause"]
+}
+
+resource "runscope_test" "test" {
+  bucket_id   = "${runscope_bucket.bucket.id}"
+  name        = "runscope test"
+  description = "This is a test with pause and ghost inspector steps"
+}
+
+resource "runscope_bucket" "bucket" {
+  name      = "terraform-provider-test"
+  team_uuid = "%s"
+}
+`
@@ -4,9 +4,12 @@
 package runscope
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"strings"
+	"reflect"
+	"sort"
+	"time"
 
 	"github.com/ewilde/go-runscope"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -18,6 +21,17 @@ func resourceRunscopeTest() *schema.Resource {
 		Read:   resourceTestRead,
 		Update: resourceTestUpdate,
 		Delete: resourceTestDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceTestImport,
+		},
+		CustomizeDiff: resourceTestCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"bucket_id": &schema.Schema{
@@ -39,12 +53,26 @@ func resourceRunscopeTest() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"step": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: stepSchema(map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					}),
+				},
+			},
 		},
 	}
 }
 
 func resourceTestCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutCreate))
+	defer cancel()
 
 	name := d.Get("name").(string)
 	log.Printf("[INFO] Creating test with name: %s", name)
@@ -54,9 +82,9 @@ func resourceTestCreate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Failed to create test: %s", err)
 	}
 
-	log.Printf("[DEBUG] test create: %#v", test)
+	log.Printf("[DEBUG] test create: %s", runscope.Redact(test))
 
-	createdTest, err := client.CreateTest(test)
+	createdTest, err := client.CreateTest(ctx, test)
 	if err != nil {
 		return fmt.Errorf("Failed to create test: %s", err)
 	}
@@ -64,20 +92,28 @@ func resourceTestCreate(d *schema.ResourceData, meta interface{}) error {
 	d.SetId(createdTest.ID)
 	log.Printf("[INFO] test ID: %s", d.Id())
 
+	if _, ok := d.GetOk("step"); ok {
+		if err := updateTestSteps(ctx, client, d, createdTest.Bucket.Key, createdTest.ID); err != nil {
+			return err
+		}
+	}
+
 	return resourceTestRead(d, meta)
 }
 
 func resourceTestRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutRead))
+	defer cancel()
 
 	testFromResource, err := createTestFromResourceData(d)
 	if err != nil {
 		return fmt.Errorf("Error reading test: %s", err)
 	}
 
-	test, err := client.ReadTest(testFromResource)
+	test, err := client.ReadTest(ctx, testFromResource)
 	if err != nil {
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "403") {
+		if runscope.IsNotFound(err) {
 			d.SetId("")
 			return nil
 		}
@@ -88,6 +124,12 @@ func resourceTestRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("name", test.Name)
 	d.Set("description", test.Description)
 	d.Set("default_environment_id", test.DefaultEnvironmentID)
+
+	// steps are only read back when managed by this resource rather than runscope_step resources
+	if _, ok := d.GetOk("step"); ok {
+		d.Set("step", readSteps(test.Steps))
+	}
+
 	return nil
 }
 
@@ -98,20 +140,30 @@ func resourceTestUpdate(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Error updating test: %s", err)
 	}
 
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
 	if d.HasChange("description") {
-		client := meta.(*runscope.Client)
-		_, err = client.UpdateTest(testFromResource)
+		_, err = client.UpdateTest(ctx, testFromResource)
 
 		if err != nil {
 			return fmt.Errorf("Error updating test: %s", err)
 		}
 	}
 
-	return nil
+	if d.HasChange("step") {
+		if err := updateTestSteps(ctx, client, d, testFromResource.Bucket.Key, testFromResource.ID); err != nil {
+			return err
+		}
+	}
+
+	return resourceTestRead(d, meta)
 }
 
 func resourceTestDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*runscope.Client)
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutDelete))
+	defer cancel()
 
 	test, err := createTestFromResourceData(d)
 	if err != nil {
@@ -119,13 +171,25 @@ func resourceTestDelete(d *schema.ResourceData, meta interface{}) error {
 	}
 	log.Printf("[INFO] Deleting test with id: %s name: %s", test.ID, test.Name)
 
-	if err := client.DeleteTest(test); err != nil {
+	if err := client.DeleteTest(ctx, test); err != nil {
 		return fmt.Errorf("Error deleting test: %s", err)
 	}
 
 	return nil
 }
 
+func resourceTestImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts, err := parseImportID(d.Id(), "bucket_key/test_id")
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("bucket_id", parts[0])
+	d.SetId(parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func createTestFromResourceData(d *schema.ResourceData) (*runscope.Test, error) {
 
 	test := runscope.NewTest()
@@ -144,3 +208,215 @@ func createTestFromResourceData(d *schema.ResourceData) (*runscope.Test, error)
 
 	return test, nil
 }
+
+// updateTestSteps makes the steps of the test match the configured step blocks. Existing
+// steps are kept when unchanged, otherwise updated in place, steps are created or deleted
+// to make up the difference and finally reordered to match the configuration
+func updateTestSteps(ctx context.Context, client *runscopeClient, d *schema.ResourceData, bucketID string, testID string) error {
+	desired := expandSteps(d.Get("step").([]interface{}))
+
+	// the current steps are read rather than taken from the state, which
+	// may be missing steps created outside of terraform
+	test, err := client.ReadTest(ctx, &runscope.Test{ID: testID, Bucket: &runscope.Bucket{Key: bucketID}})
+	if err != nil {
+		return fmt.Errorf("Error reading steps: %s", err)
+	}
+
+	current := test.Steps
+
+	used := make([]bool, len(current))
+	for _, step := range desired {
+		for i, existing := range current {
+			if !used[i] && stepsEqual(step, existing) {
+				step.ID = existing.ID
+				used[i] = true
+				break
+			}
+		}
+	}
+
+	for _, step := range desired {
+		if step.ID != "" {
+			continue
+		}
+
+		for i, existing := range current {
+			if !used[i] {
+				step.ID = existing.ID
+				used[i] = true
+				log.Printf("[INFO] Updating step %s of test %s", step.ID, testID)
+				if _, err := client.UpdateTestStep(ctx, step, bucketID, testID); err != nil {
+					return fmt.Errorf("Error updating step: %s", err)
+				}
+				break
+			}
+		}
+	}
+
+	order := []string{}
+	for i, existing := range current {
+		if !used[i] {
+			log.Printf("[WARN] Deleting step %s of test %s as it does not match a step block, steps must be "+
+				"managed either by step blocks or by runscope_step resources, not both", existing.ID, testID)
+			if err := client.DeleteTestStep(ctx, existing, bucketID, testID); err != nil {
+				return fmt.Errorf("Error deleting step: %s", err)
+			}
+			continue
+		}
+
+		order = append(order, existing.ID)
+	}
+
+	created := false
+	for _, step := range desired {
+		if step.ID != "" {
+			continue
+		}
+
+		log.Printf("[INFO] Creating step of test %s", testID)
+		createdStep, err := client.CreateTestStep(ctx, step, bucketID, testID)
+		if err != nil {
+			return fmt.Errorf("Failed to create step: %s", err)
+		}
+
+		step.ID = createdStep.ID
+		created = true
+	}
+
+	// new steps are not necessarily added at the end of the test, so the order is read back
+	if created {
+		test, err := client.ReadTest(ctx, &runscope.Test{ID: testID, Bucket: &runscope.Bucket{Key: bucketID}})
+		if err != nil {
+			return fmt.Errorf("Error reading steps: %s", err)
+		}
+
+		order = order[:0]
+		for _, step := range test.Steps {
+			order = append(order, step.ID)
+		}
+	}
+
+	for i, step := range desired {
+		if i >= len(order) || order[i] != step.ID {
+			log.Printf("[INFO] Reordering steps of test %s", testID)
+			if _, err := client.ReorderTestSteps(ctx, desired, bucketID, testID); err != nil {
+				return fmt.Errorf("Error reordering steps: %s", err)
+			}
+			break
+		}
+	}
+
+	return nil
+}
+
+// resourceTestCustomizeDiff checks each step block the same way as a runscope_step, so an
+// invalid step is reported by the plan rather than after the test is created
+func resourceTestCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("step") {
+		return nil
+	}
+
+	for i, item := range d.Get("step").([]interface{}) {
+		if !stepValuesKnown(d, fmt.Sprintf("step.%d.", i)) {
+			continue
+		}
+
+		if err := validateStep(expandStep(item.(map[string]interface{}))); err != nil {
+			return fmt.Errorf("Invalid step %d: %s", i, err)
+		}
+	}
+
+	return nil
+}
+
+func expandSteps(items []interface{}) []*runscope.TestStep {
+	steps := make([]*runscope.TestStep, 0, len(items))
+	for _, x := range items {
+		steps = append(steps, expandStep(x.(map[string]interface{})))
+	}
+
+	return steps
+}
+
+func stepsEqual(a *runscope.TestStep, b *runscope.TestStep) bool {
+	return reflect.DeepEqual(normalizeStep(a), normalizeStep(b))
+}
+
+// normalizeStep copies the configurable fields of a step in a canonical form, so
+// a step read from the api can be compared with one expanded from the configuration
+func normalizeStep(step *runscope.TestStep) *runscope.TestStep {
+	normalized := &runscope.TestStep{
+		StepType:        step.StepType,
+		Method:          step.Method,
+		URL:             step.URL,
+		Body:            step.Body,
+		LeftValue:       step.LeftValue,
+		Comparison:      step.Comparison,
+		RightValue:      step.RightValue,
+		TestUUID:        step.TestUUID,
+		BucketKey:       step.BucketKey,
+		EnvironmentUUID: step.EnvironmentUUID,
+		Params:          []*runscope.SubtestParam{},
+		Duration:        step.Duration,
+		GhostTestID:     step.GhostTestID,
+		StartURL:        step.StartURL,
+		Variables:       []*runscope.Variable{},
+		Assertions:      []*runscope.Assertion{},
+		Headers:         map[string][]string{},
+		Auth:            map[string]string{},
+		Scripts:         append([]string{}, step.Scripts...),
+		BeforeScripts:   append([]string{}, step.BeforeScripts...),
+	}
+
+	for _, variable := range step.Variables {
+		normalized.Variables = append(normalized.Variables, variable)
+	}
+
+	sort.Slice(normalized.Variables, func(i, j int) bool {
+		return fmt.Sprint(*normalized.Variables[i]) < fmt.Sprint(*normalized.Variables[j])
+	})
+
+	for _, assertion := range step.Assertions {
+		value := ""
+		if assertion.Value != nil {
+			value = fmt.Sprint(assertion.Value)
+		}
+
+		normalized.Assertions = append(normalized.Assertions, &runscope.Assertion{
+			Source:     assertion.Source,
+			Property:   assertion.Property,
+			Comparison: assertion.Comparison,
+			Value:      value,
+		})
+	}
+
+	for _, param := range step.Params {
+		normalized.Params = append(normalized.Params, param)
+	}
+
+	sort.Slice(normalized.Params, func(i, j int) bool {
+		return normalized.Params[i].Name < normalized.Params[j].Name
+	})
+
+	for header, values := range step.Headers {
+		normalized.Headers[header] = append([]string{}, values...)
+		sort.Strings(normalized.Headers[header])
+	}
+
+	for key, value := range step.Auth {
+		normalized.Auth[key] = value
+	}
+
+	return normalized
+}
+
+func readSteps(steps []*runscope.TestStep) []interface{} {
+	result := make([]interface{}, 0, len(steps))
+	for _, step := range steps {
+		item := flattenStep(step)
+		item["id"] = step.ID
+		result = append(result, item)
+	}
+
+	return result
+}
@@ -0,0 +1,299 @@
+package runscope
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ewilde/go-runscope"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceRunscopeTestRun() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTestRunCreate,
+		Read:   resourceTestRunRead,
+		Update: resourceTestRunUpdate,
+		Delete: resourceTestRunDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"bucket_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"test_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"environment_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"region": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"variables": &schema.Schema{
+				Type:     schema.TypeMap,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Optional: true,
+				ForceNew: true,
+			},
+			"triggers": &schema.Schema{
+				Type:     schema.TypeMap,
+				Optional: true,
+				ForceNew: true,
+			},
+			"fail_on_failure": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"test_run_ids": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"test_run_url": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"assertions_defined": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"assertions_passed": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"assertions_failed": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"started_at": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"finished_at": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"messages": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceTestRunCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	bucketID := d.Get("bucket_id").(string)
+	testID := d.Get("test_id").(string)
+	test, err := client.ReadTest(ctx, &runscope.Test{ID: testID, Bucket: &runscope.Bucket{Key: bucketID}})
+	if err != nil {
+		return fmt.Errorf("Failed to read test %s to trigger: %s", testID, err)
+	}
+
+	trigger := &runscope.TestTrigger{
+		TriggerURL:    test.TriggerURL,
+		EnvironmentID: d.Get("environment_id").(string),
+		Region:        d.Get("region").(string),
+		Variables:     map[string]string{},
+	}
+
+	for k, v := range d.Get("variables").(map[string]interface{}) {
+		trigger.Variables[k] = v.(string)
+	}
+
+	log.Printf("[INFO] Triggering test %s", testID)
+	triggered, err := client.TriggerTest(ctx, trigger)
+	if err != nil {
+		return fmt.Errorf("Failed to trigger test: %s", err)
+	}
+
+	if len(triggered.Runs) == 0 {
+		return fmt.Errorf("Failed to trigger test %s, no test runs were started", testID)
+	}
+
+	testRunIDs := make([]string, 0, len(triggered.Runs))
+	for _, run := range triggered.Runs {
+		testRunIDs = append(testRunIDs, run.TestRunID)
+	}
+
+	d.SetId(testRunIDs[0])
+	d.Set("test_run_ids", testRunIDs)
+	log.Printf("[INFO] test run IDs: %s", strings.Join(testRunIDs, ", "))
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"init", "queued", "working"},
+		Target:     []string{"pass", "fail", "canceled"},
+		Refresh:    testRunStateRefreshFunc(ctx, client, bucketID, testID, testRunIDs),
+		Timeout:    d.Timeout(schema.TimeoutCreate),
+		Delay:      2 * time.Second,
+		MinTimeout: 2 * time.Second,
+	}
+
+	if _, err = stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for test run %s to finish: %s", d.Id(), err)
+	}
+
+	if err := resourceTestRunRead(d, meta); err != nil {
+		return err
+	}
+
+	if status := d.Get("status").(string); status != "pass" && d.Get("fail_on_failure").(bool) {
+		return fmt.Errorf("Test run %s finished with status %s:\n%s", d.Get("test_run_url").(string),
+			status, strings.Join(expandStringList(d.Get("messages").([]interface{})), "\n"))
+	}
+
+	return nil
+}
+
+func resourceTestRunRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*runscopeClient)
+	ctx, cancel := client.newContext(d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	bucketID := d.Get("bucket_id").(string)
+	testID := d.Get("test_id").(string)
+	testRunIDs := expandStringList(d.Get("test_run_ids").([]interface{}))
+	if len(testRunIDs) == 0 {
+		testRunIDs = []string{d.Id()}
+	}
+
+	results, err := readTestResults(ctx, client, bucketID, testID, testRunIDs)
+	if err != nil {
+		if runscope.IsNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Couldn't find test run: %s", err)
+	}
+
+	d.Set("test_run_ids", testRunIDs)
+	d.Set("test_run_url", results[0].TestRunURL)
+	d.Set("status", testResultsStatus(results))
+
+	assertionsDefined, assertionsPassed, assertionsFailed := 0, 0, 0
+	messages := []string{}
+	for _, result := range results {
+		assertionsDefined += result.AssertionsDefined
+		assertionsPassed += result.AssertionsPassed
+		assertionsFailed += result.AssertionsFailed
+		messages = append(messages, result.Messages()...)
+	}
+
+	d.Set("assertions_defined", assertionsDefined)
+	d.Set("assertions_passed", assertionsPassed)
+	d.Set("assertions_failed", assertionsFailed)
+	d.Set("messages", messages)
+	d.Set("started_at", "")
+	d.Set("finished_at", "")
+	if results[0].StartedAt != nil {
+		d.Set("started_at", results[0].StartedAt.UTC().Format(time.RFC3339))
+	}
+
+	if finishedAt := testResultsFinishedAt(results); finishedAt != nil {
+		d.Set("finished_at", finishedAt.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+func resourceTestRunUpdate(d *schema.ResourceData, meta interface{}) error {
+	return resourceTestRunRead(d, meta)
+}
+
+func resourceTestRunDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[INFO] Removing test run %s from state, test runs can not be deleted", d.Id())
+	d.SetId("")
+
+	return nil
+}
+
+func testRunStateRefreshFunc(ctx context.Context, client *runscopeClient, bucketID string, testID string, testRunIDs []string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		results, err := readTestResults(ctx, client, bucketID, testID, testRunIDs)
+		if err != nil {
+			return nil, "", err
+		}
+
+		for _, result := range results {
+			if !result.Finished() {
+				log.Printf("[DEBUG] test run %s status: %s", result.TestRunID, result.Result)
+				return results, result.Result, nil
+			}
+		}
+
+		return results, testResultsStatus(results), nil
+	}
+}
+
+func readTestResults(ctx context.Context, client *runscopeClient, bucketID string, testID string, testRunIDs []string) ([]*runscope.TestResult, error) {
+	results := make([]*runscope.TestResult, 0, len(testRunIDs))
+	for _, testRunID := range testRunIDs {
+		result, err := client.ReadTestResult(ctx, testRunID, bucketID, testID)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// A trigger can start a run in each region of the environment, the
+// overall status only passes when every run passes
+func testResultsStatus(results []*runscope.TestResult) string {
+	status := "pass"
+	for _, result := range results {
+		switch {
+		case !result.Finished():
+			return result.Result
+		case result.Result == "fail":
+			status = "fail"
+		case result.Result == "canceled" && status == "pass":
+			status = "canceled"
+		}
+	}
+
+	return status
+}
+
+func testResultsFinishedAt(results []*runscope.TestResult) *time.Time {
+	var finishedAt *time.Time
+	for _, result := range results {
+		if result.FinishedAt == nil {
+			return nil
+		}
+
+		if finishedAt == nil || result.FinishedAt.After(*finishedAt) {
+			finishedAt = result.FinishedAt
+		}
+	}
+
+	return finishedAt
+}
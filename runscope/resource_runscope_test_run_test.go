@@ -0,0 +1,111 @@
+package runscope
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/ewilde/go-runscope"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccTestRun_basic(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testRunscopeTestRunConfigA, teamID, "200"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"runscope_test_run.smoke", "status", "pass"),
+					resource.TestCheckResourceAttr(
+						"runscope_test_run.smoke", "assertions_defined", "1"),
+					resource.TestCheckResourceAttr(
+						"runscope_test_run.smoke", "assertions_passed", "1"),
+					resource.TestCheckResourceAttrSet(
+						"runscope_test_run.smoke", "finished_at"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccTestRun_fail_on_failure(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+	testAccTest(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testRunscopeTestRunConfigA, teamID, "500"),
+				ExpectError: regexp.MustCompile("finished with status fail"),
+			},
+		},
+	})
+}
+
+func TestTestResultsStatus(t *testing.T) {
+	cases := []struct {
+		results  []string
+		expected string
+	}{
+		{[]string{"pass"}, "pass"},
+		{[]string{"pass", "fail"}, "fail"},
+		{[]string{"canceled", "pass"}, "canceled"},
+		{[]string{"canceled", "fail"}, "fail"},
+		{[]string{"pass", "working"}, "working"},
+		{[]string{"queued", "fail"}, "queued"},
+	}
+
+	for _, c := range cases {
+		results := []*runscope.TestResult{}
+		for _, result := range c.results {
+			results = append(results, &runscope.TestResult{Result: result})
+		}
+
+		if actual := testResultsStatus(results); actual != c.expected {
+			t.Fatalf("Expected status %s for results %v, actual %s", c.expected, c.results, actual)
+		}
+	}
+}
+
+const testRunscopeTestRunConfigA = `
+resource "runscope_test_run" "smoke" {
+  bucket_id      = "${runscope_bucket.bucket.id}"
+  test_id        = "${runscope_step.main_page.test_id}"
+  environment_id = "${runscope_test.test.default_environment_id}"
+
+  triggers {
+    step_id = "${runscope_step.main_page.id}"
+  }
+}
+
+resource "runscope_step" "main_page" {
+  bucket_id      = "${runscope_bucket.bucket.id}"
+  test_id        = "${runscope_test.test.id}"
+  step_type      = "request"
+  url            = "http://example.com"
+  method         = "GET"
+  assertions     = [
+  	{
+  	   source     = "response_status"
+           comparison = "equal_number"
+           value      = "%[2]s"
+  	},
+  ]
+}
+
+resource "runscope_test" "test" {
+  bucket_id   = "${runscope_bucket.bucket.id}"
+  name        = "runscope test"
+  description = "This is a test test..."
+}
+
+resource "runscope_bucket" "bucket" {
+  name      = "terraform-provider-test"
+  team_uuid = "%[1]s"
+}
+`
@@ -1,11 +1,15 @@
 package runscope
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/ewilde/go-runscope"
+	tfconfig "github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -14,7 +18,7 @@ func TestAccTest_basic(t *testing.T) {
 	var test runscope.Test
 	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
 
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
 		CheckDestroy: testAccCheckTestDestroy,
@@ -29,19 +33,132 @@ func TestAccTest_basic(t *testing.T) {
 						"runscope_test.test", "description", "This is a test test..."),
 				),
 			},
+			{
+				ResourceName:      "runscope_test.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("runscope_test.test", "bucket_id"),
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
 
+func TestAccTest_steps(t *testing.T) {
+	var stepID string
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckTestDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testRunscopeTestConfigSteps, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckTestSteps("runscope_test.test", "http://example.com/a", "http://example.com/b"),
+					resource.TestCheckResourceAttr("runscope_test.test", "step.#", "2"),
+					resource.TestCheckResourceAttr("runscope_test.test", "step.0.url", "http://example.com/a"),
+					resource.TestCheckResourceAttr("runscope_test.test", "step.0.assertions.0.value", "200"),
+					resource.TestCheckResourceAttr("runscope_test.test", "step.1.headers.#", "1"),
+					testAccCheckTestStepID("runscope_test.test", 0, &stepID, false),
+				),
+			},
+			{
+				ResourceName:      "runscope_test.test",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("runscope_test.test", "bucket_id"),
+				ImportStateVerify: true,
+				// steps are not imported, step blocks adopt the existing steps on the next apply
+				ImportStateVerifyIgnore: []string{"step"},
+			},
+			{
+				Config: fmt.Sprintf(testRunscopeTestConfigStepsReordered, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckTestSteps("runscope_test.test", "http://example.com/c", "http://example.com/b", "http://example.com/a"),
+					resource.TestCheckResourceAttr("runscope_test.test", "step.#", "3"),
+					testAccCheckTestStepID("runscope_test.test", 2, &stepID, true),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testRunscopeTestConfigStepsRemoved, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckTestSteps("runscope_test.test", "http://example.com/d"),
+					resource.TestCheckResourceAttr("runscope_test.test", "step.#", "1"),
+					resource.TestCheckResourceAttr("runscope_test.test", "step.0.method", "POST"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccTest_import_step_resources(t *testing.T) {
+	teamID := os.Getenv("RUNSCOPE_TEAM_ID")
+
+	testAccTest(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckTestDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testRunscopeStepConfigMultipleSteps, teamID),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStepExists("runscope_step.step_a"),
+					testAccCheckStepExists("runscope_step.step_b"),
+				),
+			},
+			{
+				ResourceName:      "runscope_test.test_a",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIDFunc("runscope_test.test_a", "bucket_id"),
+				ImportStateVerify: true,
+				ImportStateCheck:  testAccCheckImportedTestPlanEmpty("runscope test a", "This is a test a"),
+			},
+		},
+	})
+}
+
+func TestStepsEqual(t *testing.T) {
+	configured := &runscope.TestStep{
+		StepType:      "request",
+		Method:        "GET",
+		URL:           "http://example.com",
+		Variables:     []*runscope.Variable{},
+		Assertions:    []*runscope.Assertion{{Source: "response_status", Comparison: "equal_number", Value: "200"}},
+		Headers:       map[string][]string{"Accept": {"text/html", "application/json"}},
+		Auth:          map[string]string{},
+		Scripts:       []string{},
+		BeforeScripts: []string{},
+	}
+
+	read := &runscope.TestStep{
+		ID:         "0a8c4a8c-a7fb-4d4e-bc6f-8a8f1b1c3d6e",
+		RequestID:  "9e4a7d3c-8b2f-4c4e-9a1d-2f7e3c5b6a4d",
+		StepType:   "request",
+		Method:     "GET",
+		URL:        "http://example.com",
+		Assertions: []*runscope.Assertion{{Source: "response_status", Comparison: "equal_number", Value: float64(200)}},
+		Headers:    map[string][]string{"Accept": {"application/json", "text/html"}},
+	}
+
+	if !stepsEqual(configured, read) {
+		t.Fatalf("Expected steps to be equal:\n%#v\n%#v", normalizeStep(configured), normalizeStep(read))
+	}
+
+	read.URL = "http://example.com/changed"
+	if stepsEqual(configured, read) {
+		t.Fatal("Expected steps with different urls not to be equal")
+	}
+}
+
 func testAccCheckTestDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*runscope.Client)
+	client := testAccProvider.Meta().(*runscopeClient)
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "runscope_test" {
 			continue
 		}
 
-		_, err := client.ReadTest(&runscope.Test{ID: rs.Primary.ID, Bucket: &runscope.Bucket{Key: rs.Primary.Attributes["bucket_id"]}})
+		_, err := client.ReadTest(context.Background(), &runscope.Test{ID: rs.Primary.ID, Bucket: &runscope.Bucket{Key: rs.Primary.Attributes["bucket_id"]}})
 
 		if err == nil {
 			return fmt.Errorf("Record %s still exists", rs.Primary.ID)
@@ -63,9 +180,9 @@ func testAccCheckTestExists(n string, test *runscope.Test) resource.TestCheckFun
 			return fmt.Errorf("No Record ID is set")
 		}
 
-		client := testAccProvider.Meta().(*runscope.Client)
+		client := testAccProvider.Meta().(*runscopeClient)
 
-		foundRecord, err := client.ReadTest(&runscope.Test{ID: rs.Primary.ID, Bucket: &runscope.Bucket{Key: rs.Primary.Attributes["bucket_id"]}})
+		foundRecord, err := client.ReadTest(context.Background(), &runscope.Test{ID: rs.Primary.ID, Bucket: &runscope.Bucket{Key: rs.Primary.Attributes["bucket_id"]}})
 
 		if err != nil {
 			return err
@@ -81,6 +198,87 @@ func testAccCheckTestExists(n string, test *runscope.Test) resource.TestCheckFun
 	}
 }
 
+func testAccCheckTestSteps(n string, urls ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*runscopeClient)
+		test, err := client.ReadTest(context.Background(), &runscope.Test{ID: rs.Primary.ID, Bucket: &runscope.Bucket{Key: rs.Primary.Attributes["bucket_id"]}})
+		if err != nil {
+			return err
+		}
+
+		actual := []string{}
+		for _, step := range test.Steps {
+			actual = append(actual, step.URL)
+		}
+
+		if !reflect.DeepEqual(actual, urls) {
+			return fmt.Errorf("Expected steps %v, actual %v", urls, actual)
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckImportedTestPlanEmpty checks planning an imported test against its configuration,
+// with the steps managed by runscope_step resources, leaves the steps alone
+func testAccCheckImportedTestPlanEmpty(name string, description string) resource.ImportStateCheckFunc {
+	return func(states []*terraform.InstanceState) error {
+		if len(states) != 1 {
+			return fmt.Errorf("Expected 1 imported test, actual %d", len(states))
+		}
+
+		rawConfig, err := tfconfig.NewRawConfig(map[string]interface{}{
+			"bucket_id":   states[0].Attributes["bucket_id"],
+			"name":        name,
+			"description": description,
+		})
+		if err != nil {
+			return err
+		}
+
+		diff, err := resourceRunscopeTest().Diff(states[0], terraform.NewResourceConfig(rawConfig), testAccProvider.Meta())
+		if err != nil {
+			return err
+		}
+
+		if !diff.Empty() {
+			changed := []string{}
+			for key := range diff.Attributes {
+				changed = append(changed, key)
+			}
+
+			sort.Strings(changed)
+			return fmt.Errorf("Expected an empty plan after importing the test, actual changes to %v", changed)
+		}
+
+		return nil
+	}
+}
+
+// testAccCheckTestStepID records the id of the step at the given index, or when
+// same is set checks the id matches the one previously recorded
+func testAccCheckTestStepID(n string, index int, id *string, same bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		stepID := rs.Primary.Attributes[fmt.Sprintf("step.%d.id", index)]
+		if same && stepID != *id {
+			return fmt.Errorf("Expected step %d to keep id %s, actual %s", index, *id, stepID)
+		}
+
+		*id = stepID
+		return nil
+	}
+}
+
 const testRunscopeTestConfigA = `
 resource "runscope_test" "test" {
   bucket_id = "${runscope_bucket.bucket.id}"
@@ -93,3 +291,105 @@ resource "runscope_bucket" "bucket" {
   team_uuid = "%s"
 }
 `
+
+const testRunscopeTestConfigSteps = `
+resource "runscope_test" "test" {
+  bucket_id = "${runscope_bucket.bucket.id}"
+  name = "runscope test"
+  description = "This is a test with inline steps"
+
+  step {
+    step_type = "request"
+    url = "http://example.com/a"
+    method = "GET"
+    assertions = [
+      {
+        source = "response_status"
+        comparison = "equal_number"
+        value = "200"
+      }
+    ]
+  }
+
+  step {
+    step_type = "request"
+    url = "http://example.com/b"
+    method = "GET"
+    headers = [
+      {
+        header = "Accept"
+        value = "application/json"
+      }
+    ]
+  }
+}
+
+resource "runscope_bucket" "bucket" {
+  name = "terraform-provider-test"
+  team_uuid = "%s"
+}
+`
+
+const testRunscopeTestConfigStepsReordered = `
+resource "runscope_test" "test" {
+  bucket_id = "${runscope_bucket.bucket.id}"
+  name = "runscope test"
+  description = "This is a test with inline steps"
+
+  step {
+    step_type = "request"
+    url = "http://example.com/c"
+    method = "GET"
+  }
+
+  step {
+    step_type = "request"
+    url = "http://example.com/b"
+    method = "GET"
+    headers = [
+      {
+        header = "Accept"
+        value = "application/json"
+      }
+    ]
+  }
+
+  step {
+    step_type = "request"
+    url = "http://example.com/a"
+    method = "GET"
+    assertions = [
+      {
+        source = "response_status"
+        comparison = "equal_number"
+        value = "200"
+      }
+    ]
+  }
+}
+
+resource "runscope_bucket" "bucket" {
+  name = "terraform-provider-test"
+  team_uuid = "%s"
+}
+`
+
+const testRunscopeTestConfigStepsRemoved = `
+resource "runscope_test" "test" {
+  bucket_id = "${runscope_bucket.bucket.id}"
+  name = "runscope test"
+  description = "This is a test with inline steps"
+
+  step {
+    step_type = "request"
+    url = "http://example.com/d"
+    method = "POST"
+    body = "{}"
+  }
+}
+
+resource "runscope_bucket" "bucket" {
+  name = "terraform-provider-test"
+  team_uuid = "%s"
+}
+`
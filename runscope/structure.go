@@ -1,5 +1,13 @@
 package runscope
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
 // Takes the result of flatmap.Expand for an array of strings
 // and returns a []*string
 func expandStringList(configured []interface{}) []string {
@@ -21,3 +29,87 @@ func contains(slice []string, search string) bool {
 	}
 	return false
 }
+
+// Splits a composite import id i.e. bucket_key/test_id/step_id into its
+// parts, format is used to describe the expected id in the returned error
+func parseImportID(id string, format string) ([]string, error) {
+	expected := len(strings.Split(format, "/"))
+	parts := strings.Split(id, "/")
+	if len(parts) != expected {
+		return nil, fmt.Errorf("Invalid import id %q, expected format %s", id, format)
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("Invalid import id %q, expected format %s", id, format)
+		}
+	}
+
+	return parts, nil
+}
+
+// Creates a set for the given schema, required when setting a set nested
+// within a list as the items are not converted
+func newSet(s *schema.Schema, items []map[string]interface{}) *schema.Set {
+	set := s.ZeroValue().(*schema.Set)
+	for _, item := range items {
+		set.Add(item)
+	}
+
+	return set
+}
+
+// Schema for the filter blocks used by data sources returning lists, names
+// describes the fields that can be filtered on
+func dataSourceFiltersSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		ForceNew: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"values": {
+					Type:     schema.TypeSet,
+					Required: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+// Tests the fields of an item against every filter, a filter passes when the
+// named field matches any of its values
+func filtersTest(fields map[string]string, filters *schema.Set) (bool, error) {
+	for _, v := range filters.List() {
+		m := v.(map[string]interface{})
+		field, ok := fields[m["name"].(string)]
+		if !ok {
+			names := make([]string, 0, len(fields))
+			for name := range fields {
+				names = append(names, name)
+			}
+
+			sort.Strings(names)
+			return false, fmt.Errorf("Invalid filter name %q, expected one of: %s",
+				m["name"].(string), strings.Join(names, ", "))
+		}
+
+		passed := false
+		for _, e := range m["values"].(*schema.Set).List() {
+			if field == e.(string) {
+				passed = true
+			}
+		}
+
+		if !passed {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
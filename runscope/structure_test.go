@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform/flatmap"
+	"github.com/hashicorp/terraform/helper/schema"
 )
 
 func TestExpandStringList(t *testing.T) {
@@ -23,6 +24,55 @@ func TestExpandStringList(t *testing.T) {
 	}
 }
 
+func TestParseImportID(t *testing.T) {
+	parts, err := parseImportID("bucket/test/step", "bucket_key/test_id/step_id")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := []string{"bucket", "test", "step"}
+	if !reflect.DeepEqual(parts, expected) {
+		t.Fatalf("Got:\n\n%#v\n\nExpected:\n\n%#v\n", parts, expected)
+	}
+
+	invalid := []string{"bucket/test", "bucket//step", "bucket/test/step/extra"}
+	for _, id := range invalid {
+		if _, err := parseImportID(id, "bucket_key/test_id/step_id"); err == nil {
+			t.Fatalf("Expected an error parsing import id %q", id)
+		}
+	}
+}
+
+func TestFiltersTest(t *testing.T) {
+	filters := dataSourceFiltersSchema().ZeroValue().(*schema.Set)
+	filters.Add(map[string]interface{}{
+		"name":   "name",
+		"values": schema.NewSet(schema.HashString, []interface{}{"a", "b"}),
+	})
+
+	cases := []struct {
+		fields    map[string]string
+		expected  bool
+		expectErr bool
+	}{
+		{map[string]string{"name": "a"}, true, false},
+		{map[string]string{"name": "b"}, true, false},
+		{map[string]string{"name": "c"}, false, false},
+		{map[string]string{"key": "a"}, false, true},
+	}
+
+	for _, c := range cases {
+		passed, err := filtersTest(c.fields, filters)
+		if (err != nil) != c.expectErr {
+			t.Fatalf("%v: expected error %t, got %v", c.fields, c.expectErr, err)
+		}
+
+		if passed != c.expected {
+			t.Fatalf("%v: expected %t, got %t", c.fields, c.expected, passed)
+		}
+	}
+}
+
 func testConf() map[string]string {
 	return map[string]string{
 		"scripts.#": "2",
@@ -30,3 +80,30 @@ func testConf() map[string]string {
 		"scripts.1": "log(\"hello 2\");",
 	}
 }
+
+// testResourceDataValueEqual compares two values read from resource data,
+// sets are compared by their items rather than by pointer
+func testResourceDataValueEqual(actual interface{}, expected interface{}) bool {
+	return reflect.DeepEqual(normalizeResourceDataValue(actual), normalizeResourceDataValue(expected))
+}
+
+func normalizeResourceDataValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *schema.Set:
+		return normalizeResourceDataValue(v.List())
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			result = append(result, normalizeResourceDataValue(item))
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = normalizeResourceDataValue(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
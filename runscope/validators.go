@@ -0,0 +1,35 @@
+package runscope
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// validateStringInSlice returns a validate func checking a string is one of the valid values
+func validateStringInSlice(valid []string) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (ws []string, errors []error) {
+		value := v.(string)
+		for _, s := range valid {
+			if value == s {
+				return
+			}
+		}
+
+		errors = append(errors, fmt.Errorf("%q must be one of %s, got: %s", k, strings.Join(valid, ", "), value))
+		return
+	}
+}
+
+// validateIntBetween returns a validate func checking an int is within the inclusive range
+func validateIntBetween(min int, max int) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (ws []string, errors []error) {
+		value := v.(int)
+		if value < min || value > max {
+			errors = append(errors, fmt.Errorf("%q must be between %d and %d, got: %d", k, min, max, value))
+		}
+
+		return
+	}
+}
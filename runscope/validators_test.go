@@ -0,0 +1,100 @@
+package runscope
+
+import (
+	"testing"
+
+	tfconfig "github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestValidateStringInSlice(t *testing.T) {
+	validate := validateStringInSlice([]string{"all", "failures"})
+	cases := []struct {
+		value     string
+		expectErr bool
+	}{
+		{"all", false},
+		{"failures", false},
+		{"ALL", true},
+		{"", true},
+	}
+
+	for _, c := range cases {
+		_, errors := validate(c.value, "notify_on")
+		if (len(errors) > 0) != c.expectErr {
+			t.Fatalf("%q: expected error %t, got %v", c.value, c.expectErr, errors)
+		}
+	}
+}
+
+func TestValidateIntBetween(t *testing.T) {
+	validate := validateIntBetween(1, 180)
+	cases := []struct {
+		value     int
+		expectErr bool
+	}{
+		{1, false},
+		{180, false},
+		{0, true},
+		{181, true},
+	}
+
+	for _, c := range cases {
+		_, errors := validate(c.value, "duration")
+		if (len(errors) > 0) != c.expectErr {
+			t.Fatalf("%d: expected error %t, got %v", c.value, c.expectErr, errors)
+		}
+	}
+}
+
+func TestResourceEnumValidation(t *testing.T) {
+	step := func(attributes map[string]interface{}) map[string]interface{} {
+		raw := map[string]interface{}{"bucket_id": "bucket", "test_id": "test", "step_type": "request", "method": "GET"}
+		for k, v := range attributes {
+			raw[k] = v
+		}
+
+		return raw
+	}
+
+	cases := []struct {
+		resource  *schema.Resource
+		raw       map[string]interface{}
+		expectErr bool
+	}{
+		{resourceRunscopeStep(), step(nil), false},
+		{resourceRunscopeStep(), step(map[string]interface{}{"step_type": "requests"}), true},
+		{resourceRunscopeStep(), step(map[string]interface{}{"method": "GTE"}), true},
+		{resourceRunscopeStep(), step(map[string]interface{}{"assertions": []interface{}{
+			map[string]interface{}{"source": "response_stauts", "comparison": "equal_number", "value": "200"}}}), true},
+		{resourceRunscopeStep(), step(map[string]interface{}{"assertions": []interface{}{
+			map[string]interface{}{"source": "response_status", "comparison": "equal_numbr", "value": "200"}}}), true},
+		{resourceRunscopeStep(), step(map[string]interface{}{"variables": []interface{}{
+			map[string]interface{}{"name": "status", "source": "response_stauts"}}}), true},
+		{resourceRunscopeStep(), step(map[string]interface{}{"auth": []interface{}{
+			map[string]interface{}{"username": "user", "password": "password", "auth_type": "basci"}}}), true},
+		{resourceRunscopeStep(), step(map[string]interface{}{"condition": []interface{}{
+			map[string]interface{}{"left_value": "{{a}}", "comparison": "equals"}}}), true},
+		{resourceRunscopeStep(), map[string]interface{}{"bucket_id": "bucket", "test_id": "test", "step_type": "pause",
+			"pause": []interface{}{map[string]interface{}{"duration": 0}}}, true},
+		{resourceRunscopeStep(), map[string]interface{}{"bucket_id": "bucket", "test_id": "test", "step_type": "pause",
+			"pause": []interface{}{map[string]interface{}{"duration": 5}}}, false},
+		{resourceRunscopeSchedule(), map[string]interface{}{"bucket_id": "bucket", "test_id": "test", "environment_id": "env", "interval": "1h"}, false},
+		{resourceRunscopeSchedule(), map[string]interface{}{"bucket_id": "bucket", "test_id": "test", "environment_id": "env", "interval": "1hr"}, true},
+		{resourceRunscopeEnvironment(), map[string]interface{}{"bucket_id": "bucket", "name": "env", "regions": []interface{}{"us1", "eu1"}}, false},
+		{resourceRunscopeEnvironment(), map[string]interface{}{"bucket_id": "bucket", "name": "env", "regions": []interface{}{"us1", "eu9"}}, true},
+	}
+
+	for i, c := range cases {
+		rawConfig, err := tfconfig.NewRawConfig(c.raw)
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+
+		_, errors := c.resource.Validate(terraform.NewResourceConfig(rawConfig))
+		if (len(errors) > 0) != c.expectErr {
+			t.Fatalf("%d: expected error %t, got %v", i, c.expectErr, errors)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+package runscope
+
+// providerVersion is reported in the User-Agent of runscope api requests, releases
+// set it with -ldflags "-X github.com/ewilde/terraform-provider-runscope/runscope.providerVersion=<version>"
+var providerVersion = "dev"
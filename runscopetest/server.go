@@ -0,0 +1,1042 @@
+/*
+Package runscopetest implements an in-memory runscope api (https://www.runscope.com/docs/api)
+for testing the provider without network access or a runscope account.
+*/
+package runscopetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+)
+
+const (
+	// AccessToken is the only access token accepted by the server
+	AccessToken = "runscopetest-access-token"
+
+	// TeamID is the id of the team the access token belongs to
+	TeamID = "0b2c7cd9-5a24-4fa5-a5d2-8ad6b43cbd0f"
+
+	// OtherTeamID is the id of a second team the access token belongs to, for testing
+	// lookups are scoped to the right team
+	OtherTeamID = "5f0e3b2a-8c1d-4e7f-9a6b-2d4c8e1f3a7b"
+
+	// IntegrationDescription is the description of the slack integration seeded in the team
+	IntegrationDescription = "Slack: #runscope-terraform"
+
+	// PersonEmail is the email address of the team owner seeded in the team
+	PersonEmail = "owner@example.com"
+)
+
+// MaskedValue replaces the value of masked variables in responses
+const MaskedValue = "********"
+
+type object map[string]interface{}
+
+type bucket struct {
+	object       object
+	tests        map[string]*test
+	environments map[string]object
+}
+
+type test struct {
+	object       object
+	steps        []object
+	environments map[string]object
+	schedules    map[string]object
+	results      map[string]object
+	triggerID    string
+}
+
+// Server is an in-memory runscope api, responses use the same meta, data and error
+// envelope as the real api
+type Server struct {
+	*httptest.Server
+
+	sync.Mutex
+	buckets      map[string]*bucket
+	integrations []object
+	people       []object
+
+	// maskedVariables are initial variables whose values are masked in responses
+	maskedVariables map[string]bool
+}
+
+// NewServer starts a new server seeded with a team, its integrations and people. The
+// caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	server := &Server{
+		buckets: map[string]*bucket{},
+		integrations: []object{
+			{"id": newID(), "uuid": newID(), "type": "slack", "description": IntegrationDescription},
+			{"id": newID(), "uuid": newID(), "type": "slack", "description": "Slack: #alerts"},
+			{"id": newID(), "uuid": newID(), "type": "pagerduty", "description": "PagerDuty: on-call"},
+		},
+		people: []object{
+			{
+				"id": newID(), "uuid": newID(), "name": "Terraform Owner", "email": PersonEmail,
+				"group_name": "Owners", "created_at": unix(time.Now()), "last_login_at": unix(time.Now()),
+			},
+			{
+				"id": newID(), "uuid": newID(), "name": "Terraform Member", "email": "member@example.com",
+				"group_name": "Members", "created_at": unix(time.Now()), "last_login_at": unix(time.Now()),
+			},
+		},
+	}
+
+	server.Server = httptest.NewServer(server)
+	return server
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != fmt.Sprintf("Bearer %s", AccessToken) {
+		writeError(w, http.StatusUnauthorized, "Unauthorized, invalid access token")
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(path) == 1 && path[0] == "account":
+		s.handleAccount(w, r)
+	case len(path) == 3 && path[0] == "teams" && path[2] == "integrations":
+		s.handleTeamList(w, r, path[1], s.integrations)
+	case len(path) == 3 && path[0] == "teams" && path[2] == "people":
+		s.handleTeamList(w, r, path[1], s.people)
+	case len(path) == 3 && path[0] == "radar" && path[2] == "trigger":
+		s.handleTrigger(w, r, path[1])
+	case len(path) >= 1 && path[0] == "buckets":
+		s.handleBuckets(w, r, path[1:])
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+// MaskVariables sets the names of the initial variables whose values are masked in
+// environment responses, as runscope does for secret values. Calling it with no names
+// stops masking.
+func (s *Server) MaskVariables(names ...string) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.maskedVariables = map[string]bool{}
+	for _, name := range names {
+		s.maskedVariables[name] = true
+	}
+}
+
+func (s *Server) handleAccount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	owner := s.people[0]
+	writeData(w, http.StatusOK, object{
+		"id":         owner["id"],
+		"uuid":       owner["uuid"],
+		"name":       owner["name"],
+		"email":      owner["email"],
+		"created_at": owner["created_at"],
+		"teams": []object{
+			{"uuid": TeamID, "name": "Terraform Team"},
+			{"uuid": OtherTeamID, "name": "Other Team"},
+		},
+	})
+}
+
+func (s *Server) handleTeamList(w http.ResponseWriter, r *http.Request, teamID string, items []object) {
+	if teamID != TeamID {
+		writeError(w, http.StatusForbidden, "Forbidden, not a member of the team")
+		return
+	}
+
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	writeData(w, http.StatusOK, items)
+}
+
+func (s *Server) handleBuckets(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case "GET":
+			buckets := []object{}
+			for _, b := range s.buckets {
+				buckets = append(buckets, b.object)
+			}
+			writeData(w, http.StatusOK, buckets)
+		case "POST":
+			s.createBucket(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		}
+		return
+	}
+
+	b, ok := s.buckets[path[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Bucket %s not found", path[0]))
+		return
+	}
+
+	if len(path) == 1 {
+		switch r.Method {
+		case "GET":
+			writeData(w, http.StatusOK, b.object)
+		case "DELETE":
+			delete(s.buckets, path[0])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		}
+		return
+	}
+
+	switch path[1] {
+	case "environments":
+		s.handleEnvironments(w, r, b, nil, b.environments, path[2:])
+	case "tests":
+		s.handleTests(w, r, b, path[2:])
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) createBucket(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	teamID := r.PostForm.Get("team_uuid")
+	teamName := map[string]string{TeamID: "Terraform", OtherTeamID: "Other Team"}[teamID]
+	if teamName == "" {
+		writeError(w, http.StatusForbidden, "Forbidden, not a member of the team")
+		return
+	}
+
+	key := newKey()
+	b := &bucket{
+		object: object{
+			"key":             key,
+			"name":            r.PostForm.Get("name"),
+			"default":         false,
+			"auth_token":      newKey(),
+			"verify_ssl":      true,
+			"tests_url":       fmt.Sprintf("%s/buckets/%s/tests", s.URL, key),
+			"collections_url": fmt.Sprintf("%s/buckets/%s/collections", s.URL, key),
+			"messages_url":    fmt.Sprintf("%s/buckets/%s/messages", s.URL, key),
+			"trigger_url":     fmt.Sprintf("%s/radar/bucket/%s/trigger", s.URL, key),
+			"team":            object{"id": teamID, "name": teamName},
+		},
+		tests:        map[string]*test{},
+		environments: map[string]object{},
+	}
+
+	s.buckets[key] = b
+	writeData(w, http.StatusCreated, b.object)
+}
+
+func (s *Server) handleTests(w http.ResponseWriter, r *http.Request, b *bucket, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case "GET":
+			tests := []object{}
+			for _, t := range b.tests {
+				tests = append(tests, s.testObject(t))
+			}
+			writeData(w, http.StatusOK, tests)
+		case "POST":
+			s.createTest(w, r, b)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		}
+		return
+	}
+
+	t, ok := b.tests[path[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Test %s not found", path[0]))
+		return
+	}
+
+	if len(path) == 1 {
+		switch r.Method {
+		case "GET":
+			writeData(w, http.StatusOK, s.testObject(t))
+		case "PUT":
+			body, ok := readObject(w, r)
+			if !ok {
+				return
+			}
+
+			merge(t.object, body, "id", "steps", "environments", "trigger_url", "created_at", "last_run")
+			writeData(w, http.StatusOK, s.testObject(t))
+		case "DELETE":
+			delete(b.tests, path[0])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		}
+		return
+	}
+
+	switch path[1] {
+	case "steps":
+		s.handleSteps(w, r, t, path[2:])
+	case "environments":
+		s.handleEnvironments(w, r, b, t, t.environments, path[2:])
+	case "schedules":
+		s.handleSchedules(w, r, b, t, path[2:])
+	case "results":
+		s.handleResults(w, r, t, path[2:])
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) createTest(w http.ResponseWriter, r *http.Request, b *bucket) {
+	body, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+
+	if name, _ := body["name"].(string); name == "" {
+		writeError(w, http.StatusBadRequest, "A test name is required")
+		return
+	}
+
+	t := &test{
+		object:       object{},
+		steps:        []object{},
+		environments: map[string]object{},
+		schedules:    map[string]object{},
+		results:      map[string]object{},
+		triggerID:    newID(),
+	}
+
+	merge(t.object, body, "steps", "environments")
+	t.object["id"] = newID()
+	t.object["created_at"] = unix(time.Now())
+	t.object["created_by"] = object{"id": s.people[0]["id"], "name": s.people[0]["name"], "email": s.people[0]["email"]}
+
+	environment := s.newEnvironment(object{"name": "Test Settings"}, t)
+	t.environments[environment["id"].(string)] = environment
+	t.object["default_environment_id"] = environment["id"]
+
+	b.tests[t.object["id"].(string)] = t
+	writeData(w, http.StatusCreated, s.testObject(t))
+}
+
+func (s *Server) testObject(t *test) object {
+	result := object{}
+	merge(result, t.object)
+
+	environments := []object{}
+	for _, environment := range t.environments {
+		environments = append(environments, environment)
+	}
+
+	result["steps"] = t.steps
+	result["environments"] = environments
+	result["trigger_url"] = fmt.Sprintf("%s/radar/%s/trigger", s.URL, t.triggerID)
+	result["last_run"] = nil
+
+	return result
+}
+
+func (s *Server) handleSteps(w http.ResponseWriter, r *http.Request, t *test, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case "GET":
+			writeData(w, http.StatusOK, t.steps)
+		case "POST":
+			body, ok := readObject(w, r)
+			if !ok {
+				return
+			}
+
+			stepType, _ := body["step_type"].(string)
+			step := stepBody(stepType, body)
+			step["id"] = newID()
+			t.steps = append(t.steps, step)
+			writeData(w, http.StatusCreated, t.steps)
+		case "PUT":
+			s.reorderSteps(w, r, t)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		}
+		return
+	}
+
+	index := -1
+	for i, step := range t.steps {
+		if step["id"] == path[0] {
+			index = i
+		}
+	}
+
+	if index < 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Step %s not found", path[0]))
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeData(w, http.StatusOK, t.steps[index])
+	case "PUT":
+		body, ok := readObject(w, r)
+		if !ok {
+			return
+		}
+
+		stepType, _ := t.steps[index]["step_type"].(string)
+		if value, ok := body["step_type"].(string); ok {
+			stepType = value
+		}
+
+		merge(t.steps[index], stepBody(stepType, body))
+		writeData(w, http.StatusOK, t.steps[index])
+	case "DELETE":
+		t.steps = append(t.steps[:index], t.steps[index+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+// stepFields are the fields of each type of step documented at https://www.runscope.com/docs/api/steps,
+// along with commonStepFields. Like the runscope api, other fields sent for the type of step are
+// ignored rather than rejected, so a field the type of step does not have is missing when read back
+var stepFields = map[string][]string{
+	"request":         {"method", "url", "variables", "auth", "body", "form", "headers", "assertions", "scripts", "before_scripts"},
+	"condition":       {"left_value", "comparison", "right_value", "steps"},
+	"pause":           {"duration"},
+	"subtest":         {"test_uuid", "bucket_key", "environment_uuid", "params", "variables", "assertions"},
+	"ghost-inspector": {"test_id", "start_url", "variables", "assertions"},
+}
+
+var commonStepFields = []string{"step_type", "note", "skipped"}
+
+// stepBody returns the fields of body that belong to a step of stepType
+func stepBody(stepType string, body object) object {
+	result := object{}
+	for _, fields := range [][]string{commonStepFields, stepFields[stepType]} {
+		for _, field := range fields {
+			if value, ok := body[field]; ok {
+				result[field] = value
+			}
+		}
+	}
+
+	return result
+}
+
+// reorderSteps orders the steps of the test to match the step ids in the request,
+// like the runscope api every existing step must be included
+func (s *Server) reorderSteps(w http.ResponseWriter, r *http.Request, t *test) {
+	bodyBytes, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var body []object
+	if err := json.Unmarshal(bodyBytes, &body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid json: %s", err))
+		return
+	}
+
+	if len(body) != len(t.steps) {
+		writeError(w, http.StatusBadRequest, "Every step in the test must be included when reordering steps")
+		return
+	}
+
+	steps := make([]object, 0, len(t.steps))
+	for _, item := range body {
+		found := false
+		for _, step := range t.steps {
+			if step["id"] == item["id"] {
+				steps = append(steps, step)
+				found = true
+			}
+		}
+
+		if !found {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Step %v not found", item["id"]))
+			return
+		}
+	}
+
+	t.steps = steps
+	writeData(w, http.StatusOK, t.steps)
+}
+
+func (s *Server) handleEnvironments(w http.ResponseWriter, r *http.Request, b *bucket, t *test, environments map[string]object, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case "GET":
+			result := []object{}
+			for _, environment := range environments {
+				result = append(result, s.maskEnvironment(environment))
+			}
+			writeData(w, http.StatusOK, result)
+		case "POST":
+			body, ok := readObject(w, r)
+			if !ok {
+				return
+			}
+
+			if !validEnvironment(w, body) {
+				return
+			}
+
+			environment := s.newEnvironment(body, t)
+			environments[environment["id"].(string)] = environment
+			writeData(w, http.StatusCreated, s.maskEnvironment(environment))
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		}
+		return
+	}
+
+	environment, ok := environments[path[0]]
+	if !ok && t == nil && r.Method == "DELETE" {
+		// test specific environments are deleted using the shared environment endpoint
+		for _, bucketTest := range b.tests {
+			if _, ok := bucketTest.environments[path[0]]; ok {
+				delete(bucketTest.environments, path[0])
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+	}
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Environment %s not found", path[0]))
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeData(w, http.StatusOK, s.maskEnvironment(inheritEnvironment(b, environment)))
+	case "PUT":
+		body, ok := readObject(w, r)
+		if !ok {
+			return
+		}
+
+		if !validEnvironment(w, body) {
+			return
+		}
+
+		merge(environment, body, "id", "test_id")
+		environment["integrations"] = s.expandIntegrations(environment["integrations"])
+		writeData(w, http.StatusOK, s.maskEnvironment(environment))
+	case "DELETE":
+		delete(environments, path[0])
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+// maskEnvironment returns a copy of the environment with the values of masked variables replaced
+func (s *Server) maskEnvironment(environment object) object {
+	variables, ok := environment["initial_variables"].(map[string]interface{})
+	if !ok || len(s.maskedVariables) == 0 {
+		return environment
+	}
+
+	masked := map[string]interface{}{}
+	for k, v := range variables {
+		if s.maskedVariables[k] {
+			v = MaskedValue
+		}
+
+		masked[k] = v
+	}
+
+	result := object{}
+	merge(result, environment)
+	result["initial_variables"] = masked
+	return result
+}
+
+// validEnvironment rejects null collections, runscope replaces a collection with
+// whatever value is sent so only collections being changed should be included
+func validEnvironment(w http.ResponseWriter, body object) bool {
+	for _, field := range []string{"initial_variables", "integrations", "remote_agents", "webhooks"} {
+		if value, ok := body[field]; ok && value == nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Field %s can not be null", field))
+			return false
+		}
+	}
+
+	return true
+}
+
+// newEnvironment applies the same defaults to a new environment as the runscope api
+func (s *Server) newEnvironment(body object, t *test) object {
+	environment := object{
+		"script":            "",
+		"preserve_cookies":  false,
+		"initial_variables": map[string]interface{}{},
+		"integrations":      []interface{}{},
+		"regions":           []interface{}{"us1"},
+		"verify_ssl":        true,
+		"retry_on_failure":  false,
+		"remote_agents":     []interface{}{},
+		"webhooks":          nil,
+		"emails": object{
+			"notify_all":       false,
+			"notify_on":        "all",
+			"notify_threshold": 1,
+			"recipients":       []interface{}{},
+		},
+	}
+
+	merge(environment, body, "id")
+	if regions, ok := environment["regions"].([]interface{}); !ok || len(regions) == 0 {
+		environment["regions"] = []interface{}{"us1"}
+	}
+
+	environment["id"] = newID()
+	environment["integrations"] = s.expandIntegrations(environment["integrations"])
+	if t != nil {
+		environment["test_id"] = t.object["id"]
+	} else {
+		delete(environment, "test_id")
+	}
+
+	return environment
+}
+
+// inheritEnvironment returns a copy of the environment with the script, initial variables
+// and webhooks of its parent shared environment merged in. The merge follows the runscope
+// environment docs, values set on the environment win over the parent's, and has not been
+// checked against the live api. An environment whose parent was deleted is returned as is.
+func inheritEnvironment(b *bucket, environment object) object {
+	parentID, _ := environment["parent_environment_id"].(string)
+	parent, ok := b.environments[parentID]
+	if !ok {
+		return environment
+	}
+
+	result := object{}
+	for k, v := range environment {
+		result[k] = v
+	}
+
+	if script, _ := result["script"].(string); script == "" {
+		result["script"] = parent["script"]
+	}
+
+	variables := object{}
+	if parentVariables, ok := parent["initial_variables"].(map[string]interface{}); ok {
+		for k, v := range parentVariables {
+			variables[k] = v
+		}
+	}
+	if ownVariables, ok := environment["initial_variables"].(map[string]interface{}); ok {
+		for k, v := range ownVariables {
+			variables[k] = v
+		}
+	}
+	result["initial_variables"] = variables
+
+	webhooks := []interface{}{}
+	seen := map[interface{}]bool{}
+	for _, source := range []interface{}{parent["webhooks"], environment["webhooks"]} {
+		items, _ := source.([]interface{})
+		for _, item := range items {
+			if !seen[item] {
+				seen[item] = true
+				webhooks = append(webhooks, item)
+			}
+		}
+	}
+	result["webhooks"] = webhooks
+
+	return result
+}
+
+// expandIntegrations fills in the details of integrations referenced by id
+func (s *Server) expandIntegrations(value interface{}) []interface{} {
+	items, _ := value.([]interface{})
+	result := []interface{}{}
+	for _, item := range items {
+		id, _ := item.(map[string]interface{})["id"].(string)
+		for _, integration := range s.integrations {
+			if integration["id"] == id {
+				result = append(result, object{
+					"id":               id,
+					"uuid":             integration["uuid"],
+					"integration_type": integration["type"],
+					"description":      integration["description"],
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+func (s *Server) handleSchedules(w http.ResponseWriter, r *http.Request, b *bucket, t *test, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case "GET":
+			result := []object{}
+			for _, schedule := range t.schedules {
+				result = append(result, schedule)
+			}
+			writeData(w, http.StatusOK, result)
+		case "POST":
+			body, ok := readObject(w, r)
+			if !ok || !s.validSchedule(w, b, t, body) {
+				return
+			}
+
+			schedule := object{}
+			merge(schedule, body)
+			schedule["id"] = newID()
+			t.schedules[schedule["id"].(string)] = schedule
+			writeData(w, http.StatusCreated, schedule)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		}
+		return
+	}
+
+	schedule, ok := t.schedules[path[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Schedule %s not found", path[0]))
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeData(w, http.StatusOK, schedule)
+	case "PUT":
+		body, ok := readObject(w, r)
+		if !ok {
+			return
+		}
+
+		updated := object{}
+		merge(updated, schedule)
+		merge(updated, body, "id")
+		if !s.validSchedule(w, b, t, updated) {
+			return
+		}
+
+		merge(schedule, updated)
+		writeData(w, http.StatusOK, schedule)
+	case "DELETE":
+		delete(t.schedules, path[0])
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+	}
+}
+
+func (s *Server) validSchedule(w http.ResponseWriter, b *bucket, t *test, schedule object) bool {
+	environmentID, _ := schedule["environment_id"].(string)
+	_, shared := b.environments[environmentID]
+	_, testSpecific := t.environments[environmentID]
+	if !shared && !testSpecific {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Environment %s not found", environmentID))
+		return false
+	}
+
+	switch schedule["interval"] {
+	case "1m", "5m", "15m", "30m", "1h", "6h", "1d":
+		return true
+	}
+
+	writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid interval %v", schedule["interval"]))
+	return false
+}
+
+func (s *Server) handleTrigger(w http.ResponseWriter, r *http.Request, triggerID string) {
+	for _, b := range s.buckets {
+		for _, t := range b.tests {
+			if t.triggerID == triggerID {
+				s.triggerTest(w, r, b, t)
+				return
+			}
+		}
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("Trigger %s not found", triggerID))
+}
+
+// triggerTest runs the test immediately, request steps are not sent, instead every request
+// is treated as returning a 200 status code when evaluating assertions
+func (s *Server) triggerTest(w http.ResponseWriter, r *http.Request, b *bucket, t *test) {
+	query := r.URL.Query()
+	environmentID := query.Get("runscope_environment")
+	if environmentID == "" {
+		environmentID, _ = t.object["default_environment_id"].(string)
+	}
+
+	environment, ok := t.environments[environmentID]
+	if !ok {
+		if environment, ok = b.environments[environmentID]; !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Environment %s not found", environmentID))
+			return
+		}
+	}
+
+	regions := []interface{}{query.Get("runscope_region")}
+	if regions[0] == "" {
+		regions = environment["regions"].([]interface{})
+	}
+
+	runs := []object{}
+	for _, region := range regions {
+		result := s.runTest(b, t, environment, region.(string))
+		t.results[result["test_run_id"].(string)] = result
+		runs = append(runs, object{
+			"test_run_id":      result["test_run_id"],
+			"test_run_url":     result["test_run_url"],
+			"test_id":          t.object["id"],
+			"test_name":        t.object["name"],
+			"bucket_key":       b.object["key"],
+			"environment_id":   environment["id"],
+			"environment_name": environment["name"],
+			"region":           region,
+			"status":           "init",
+		})
+	}
+
+	writeData(w, http.StatusCreated, object{
+		"runs":         runs,
+		"runs_failed":  0,
+		"runs_started": len(runs),
+		"runs_total":   len(runs),
+	})
+}
+
+func (s *Server) runTest(b *bucket, t *test, environment object, region string) object {
+	testRunID := newID()
+	startedAt := time.Now()
+	requests := []interface{}{}
+	defined, passed := 0, 0
+	for _, step := range t.steps {
+		if step["step_type"] != "request" {
+			continue
+		}
+
+		assertions := []interface{}{}
+		stepPassed := 0
+		items, _ := step["assertions"].([]interface{})
+		for _, item := range items {
+			assertion := item.(map[string]interface{})
+			result := "pass"
+			if !evaluateAssertion(assertion) {
+				result = "fail"
+			} else {
+				stepPassed++
+			}
+
+			assertions = append(assertions, object{
+				"result":       result,
+				"source":       assertion["source"],
+				"property":     assertion["property"],
+				"comparison":   assertion["comparison"],
+				"target_value": assertion["value"],
+				"actual_value": 200,
+				"error":        "",
+			})
+		}
+
+		defined += len(items)
+		passed += stepPassed
+		requests = append(requests, object{
+			"uuid":               step["id"],
+			"method":             step["method"],
+			"url":                step["url"],
+			"result":             passOrFail(stepPassed == len(items)),
+			"assertions":         assertions,
+			"assertions_defined": len(items),
+			"assertions_passed":  stepPassed,
+			"assertions_failed":  len(items) - stepPassed,
+		})
+	}
+
+	return object{
+		"test_run_id":        testRunID,
+		"test_run_url":       fmt.Sprintf("%s/radar/%s/%s/history/%s", s.URL, b.object["key"], t.object["id"], testRunID),
+		"test_id":            t.object["id"],
+		"bucket_key":         b.object["key"],
+		"environment_id":     environment["id"],
+		"environment_name":   environment["name"],
+		"region":             region,
+		"result":             passOrFail(passed == defined),
+		"started_at":         unix(startedAt),
+		"finished_at":        unix(time.Now()),
+		"assertions_defined": defined,
+		"assertions_passed":  passed,
+		"assertions_failed":  defined - passed,
+		"requests":           requests,
+	}
+}
+
+func (s *Server) handleResults(w http.ResponseWriter, r *http.Request, t *test, path []string) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		return
+	}
+
+	if len(path) == 0 {
+		results := []object{}
+		for _, result := range t.results {
+			results = append(results, result)
+		}
+		writeData(w, http.StatusOK, results)
+		return
+	}
+
+	result, ok := t.results[path[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Test run %s not found", path[0]))
+		return
+	}
+
+	writeData(w, http.StatusOK, result)
+}
+
+// evaluateAssertion compares response status assertions against a 200 status code,
+// assertions against any other source always pass
+func evaluateAssertion(assertion map[string]interface{}) bool {
+	if assertion["source"] != "response_status" {
+		return true
+	}
+
+	var value float64
+	if _, err := fmt.Sscanf(fmt.Sprintf("%v", assertion["value"]), "%g", &value); err != nil {
+		return false
+	}
+
+	switch assertion["comparison"] {
+	case "equal", "equal_number":
+		return value == 200
+	case "not_equal":
+		return value != 200
+	case "is_less_than":
+		return 200 < value
+	case "is_less_than_or_equal":
+		return 200 <= value
+	case "is_greater_than":
+		return 200 > value
+	case "is_greater_than_or_equal":
+		return 200 >= value
+	}
+
+	return true
+}
+
+func passOrFail(pass bool) string {
+	if pass {
+		return "pass"
+	}
+
+	return "fail"
+}
+
+// merge copies the values from source into destination, skipping any of the excluded keys
+func merge(destination object, source map[string]interface{}, exclude ...string) {
+	for key, value := range source {
+		excluded := false
+		for _, e := range exclude {
+			if key == e {
+				excluded = true
+			}
+		}
+
+		if !excluded {
+			destination[key] = value
+		}
+	}
+}
+
+func readObject(w http.ResponseWriter, r *http.Request) (object, bool) {
+	bodyBytes, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	body := object{}
+	if err := json.Unmarshal(bodyBytes, &body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid json: %s", err))
+		return nil, false
+	}
+
+	return body, true
+}
+
+func writeData(w http.ResponseWriter, status int, data interface{}) {
+	writeResponse(w, status, object{
+		"meta":  object{"status": "success"},
+		"data":  data,
+		"error": nil,
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeResponse(w, status, object{
+		"meta":  object{"status": "error"},
+		"data":  []interface{}{},
+		"error": object{"status": status, "error": message},
+	})
+}
+
+func writeResponse(w http.ResponseWriter, status int, body object) {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(bodyBytes)
+}
+
+func newID() string {
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		panic(err)
+	}
+
+	return id
+}
+
+func newKey() string {
+	const letters = "abcdefghijklmnopqrstuvwxyz0123456789"
+	key := make([]byte, 12)
+	for i := range key {
+		key[i] = letters[rand.Intn(len(letters))]
+	}
+
+	return string(key)
+}
+
+func unix(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
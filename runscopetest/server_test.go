@@ -0,0 +1,112 @@
+package runscopetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ewilde/go-runscope"
+)
+
+func TestServer_unauthorized(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := runscope.NewClient(server.URL, "invalid")
+	client.MaxRetries = 0
+	_, err := client.ListIntegrations(context.Background(), TeamID)
+	if !runscope.IsUnauthorized(err) {
+		t.Errorf("Expected unauthorized error, actual: %v", err)
+	}
+}
+
+func TestServer_testLifecycle(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := runscope.NewClient(server.URL, AccessToken)
+	bucket, err := client.CreateBucket(context.Background(), &runscope.Bucket{Name: "bucket", Team: &runscope.Team{ID: TeamID}})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %s", err)
+	}
+
+	test, err := client.CreateTest(context.Background(), &runscope.Test{Name: "test", Description: "description", Bucket: bucket})
+	if err != nil {
+		t.Fatalf("Failed to create test: %s", err)
+	}
+
+	if test.DefaultEnvironmentID == "" {
+		t.Error("Expected a default environment to be created with the test")
+	}
+
+	step := runscope.NewTestStep()
+	step.StepType = "request"
+	step.Method = "GET"
+	step.URL = "http://example.com"
+	step.Assertions = []*runscope.Assertion{{Source: "response_status", Comparison: "equal_number", Value: 500}}
+	if _, err := client.CreateTestStep(context.Background(), step, bucket.Key, test.ID); err != nil {
+		t.Fatalf("Failed to create step: %s", err)
+	}
+
+	test, err = client.ReadTest(context.Background(), test)
+	if err != nil {
+		t.Fatalf("Failed to read test: %s", err)
+	}
+
+	runs, err := client.TriggerTest(context.Background(), &runscope.TestTrigger{TriggerURL: test.TriggerURL})
+	if err != nil {
+		t.Fatalf("Failed to trigger test: %s", err)
+	}
+
+	result, err := client.ReadTestResult(context.Background(), runs.Runs[0].TestRunID, bucket.Key, test.ID)
+	if err != nil {
+		t.Fatalf("Failed to read test result: %s", err)
+	}
+
+	if result.Result != "fail" || result.AssertionsFailed != 1 {
+		t.Errorf("Expected test run to fail with 1 failed assertion, actual: %s %d", result.Result, result.AssertionsFailed)
+	}
+
+	if err := client.DeleteTest(context.Background(), test); err != nil {
+		t.Fatalf("Failed to delete test: %s", err)
+	}
+
+	if _, err := client.ReadTest(context.Background(), test); !runscope.IsNotFound(err) {
+		t.Errorf("Expected not found error, actual: %v", err)
+	}
+}
+
+func TestServer_stepFields(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := runscope.NewClient(server.URL, AccessToken)
+	bucket, err := client.CreateBucket(context.Background(), &runscope.Bucket{Name: "bucket", Team: &runscope.Team{ID: TeamID}})
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %s", err)
+	}
+
+	test, err := client.CreateTest(context.Background(), &runscope.Test{Name: "test", Description: "description", Bucket: bucket})
+	if err != nil {
+		t.Fatalf("Failed to create test: %s", err)
+	}
+
+	step := runscope.NewTestStep()
+	step.StepType = "pause"
+	step.Duration = 5
+	step.URL = "http://example.com"
+	step.Variables = []*runscope.Variable{{Name: "status", Source: "response_status"}}
+	step, err = client.CreateTestStep(context.Background(), step, bucket.Key, test.ID)
+	if err != nil {
+		t.Fatalf("Failed to create step: %s", err)
+	}
+
+	step, err = client.ReadTestStep(context.Background(), step, bucket.Key, test.ID)
+	if err != nil {
+		t.Fatalf("Failed to read step: %s", err)
+	}
+
+	if step.Duration != 5 || step.URL != "" || len(step.Variables) != 0 {
+		t.Errorf("Expected only the pause fields to be kept, actual duration %d url %q variables %d",
+			step.Duration, step.URL, len(step.Variables))
+	}
+}